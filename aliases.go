@@ -0,0 +1,94 @@
+package configuro
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const deprecatedTag = "deprecated"
+
+//ErrKeyConflict Error if both a deprecated key and the key replacing it are set to different values.
+type ErrKeyConflict struct {
+	deprecatedKey string
+	key           string
+}
+
+func (e *ErrKeyConflict) Error() string {
+	return fmt.Sprintf("both deprecated config key \"%s\" and \"%s\" are set with different values", e.deprecatedKey, e.key)
+}
+
+func logWarning(warning string) {
+	log.Printf("configuro: %s", warning)
+}
+
+func (c *Config) warn(format string, args ...interface{}) {
+	if c.warningLogger != nil {
+		c.warningLogger(fmt.Sprintf(format, args...))
+	}
+}
+
+// collectKeyAliases return deprecated keys mapped to the keys replacing them,
+// from WithKeyAliases and fields of configStruct (loaded at key) tagged with `deprecated:"old.key"`.
+func (c *Config) collectKeyAliases(key string, configStruct interface{}) map[string]string {
+	aliases := make(map[string]string, len(c.keyAliases))
+	for deprecatedKey, newKey := range c.keyAliases {
+		aliases[strings.ToLower(deprecatedKey)] = strings.ToLower(newKey)
+	}
+
+	var path []string
+	if key != "" {
+		path = strings.Split(strings.ToLower(key), c.keyDelimiter)
+	}
+	walkStructFields(reflect.TypeOf(configStruct), c.tag, path, func(path []string, field reflect.StructField) bool {
+		for _, deprecatedKey := range strings.Split(field.Tag.Get(deprecatedTag), ",") {
+			if deprecatedKey = strings.TrimSpace(deprecatedKey); deprecatedKey != "" {
+				aliases[strings.ToLower(deprecatedKey)] = strings.ToLower(strings.Join(path, c.keyDelimiter))
+			}
+		}
+		return true
+	})
+
+	return aliases
+}
+
+// isSetBySource return whether key was set by a config file or an Environment Variable (not by a default).
+func (c *Config) isSetBySource(key string) bool {
+	kind := c.Source(key).Kind
+	return kind == SourceFile || kind == SourceEnv
+}
+
+// applyKeyAliases move values set under deprecated keys to the keys replacing them, and warn about every deprecated key in use.
+// If both keys are set, their values must be equal.
+func (c *Config) applyKeyAliases(aliases map[string]string) error {
+	deprecatedKeys := make([]string, 0, len(aliases))
+	for deprecatedKey := range aliases {
+		deprecatedKeys = append(deprecatedKeys, deprecatedKey)
+	}
+	sort.Strings(deprecatedKeys)
+
+	for _, deprecatedKey := range deprecatedKeys {
+		if !c.isSetBySource(deprecatedKey) {
+			continue
+		}
+		newKey := aliases[deprecatedKey]
+		value := c.viper.Get(deprecatedKey)
+		source := c.Source(deprecatedKey)
+
+		c.warn("config key \"%s\" set by %s is deprecated, use \"%s\" instead", deprecatedKey, source, newKey)
+
+		if c.isSetBySource(newKey) {
+			if fmt.Sprint(c.viper.Get(newKey)) != fmt.Sprint(value) {
+				return &ErrKeyConflict{deprecatedKey: deprecatedKey, key: newKey}
+			}
+			continue
+		}
+
+		c.viper.Set(newKey, value)
+		c.recordSource(newKey, source)
+	}
+
+	return nil
+}
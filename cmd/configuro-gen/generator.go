@@ -0,0 +1,864 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type kind int
+
+const (
+	kindScalar kind = iota
+	kindDuration
+	kindAny
+	kindStruct
+	kindPtr
+	kindSlice
+	kindMap
+)
+
+// fieldType a type the generated code can load.
+type fieldType struct {
+	kind   kind
+	goType string     // source of the type (e.g `[]string` or `Level`)
+	basic  string     // underlying basic type of scalars (e.g `int64`)
+	elem   *fieldType // element of pointers, slices, and maps
+	key    *fieldType // key of maps
+}
+
+// field a field of a config struct.
+type field struct {
+	name        string
+	key         string
+	squash      bool
+	typ         *fieldType
+	envNames    []string
+	defaultTag  string
+	hasDefault  bool
+	validateTag string
+}
+
+type generator struct {
+	pkg         string
+	specs       map[string]*ast.TypeSpec
+	validatable map[string]bool
+	tag         string
+	validateTag string
+
+	structs   []string
+	fields    map[string][]field
+	resolving map[string]bool
+	buf       bytes.Buffer
+
+	validating string   // the field whose validation is being generated (e.g `Config.Port`)
+	warnings   []string // validation rules that are skipped
+}
+
+var basicTypes = map[string]bool{
+	"string": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "rune": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "byte": true,
+	"float32": true, "float64": true,
+}
+
+// newGenerator parse the package in dir, the output file is skipped so it can be generated again.
+func newGenerator(dir string, output string, tag string, validateTag string) (*generator, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		specs:       make(map[string]*ast.TypeSpec),
+		validatable: make(map[string]bool),
+		tag:         tag,
+		validateTag: validateTag,
+		fields:      make(map[string][]field),
+		resolving:   make(map[string]bool),
+	}
+
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") || filepath.Clean(path) == filepath.Clean(output) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		g.pkg = file.Name.Name
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+						g.specs[typeSpec.Name.Name] = typeSpec
+					}
+				}
+			case *ast.FuncDecl:
+				if isValidateMethod(decl) {
+					// Only value receivers, as Config.Validate calls Validate() of values.
+					if ident, ok := decl.Recv.List[0].Type.(*ast.Ident); ok {
+						g.validatable[ident.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	if g.pkg == "" {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+	return g, nil
+}
+
+// isValidateMethod return whether decl is a `Validate() error` method (implementing configuro.Validatable).
+func isValidateMethod(decl *ast.FuncDecl) bool {
+	return decl.Recv != nil && len(decl.Recv.List) == 1 && decl.Name.Name == "Validate" &&
+		decl.Type.Params.NumFields() == 0 && decl.Type.Results.NumFields() == 1 &&
+		types.ExprString(decl.Type.Results.List[0].Type) == "error"
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) warnf(format string, args ...interface{}) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+func (g *generator) generate(typeNames []string, prefix string) error {
+	for _, name := range typeNames {
+		if _, found := g.specs[strings.TrimSpace(name)]; !found {
+			return fmt.Errorf("type %s not found in package %s", name, g.pkg)
+		}
+		t, err := g.resolve(ast.NewIdent(strings.TrimSpace(name)))
+		if err != nil {
+			return err
+		}
+		if t.kind != kindStruct {
+			return fmt.Errorf("type %s is not a struct", name)
+		}
+	}
+
+	g.printf("// Code generated by configuro-gen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", g.pkg)
+	g.printf("import \"github.com/sherifabdlnaby/configuro/loader\"\n\n")
+
+	for _, name := range typeNames {
+		name = strings.TrimSpace(name)
+		g.printf("// Load%s Load %s from config documents (e.g read using loader.ReadFile) and Environment Variables prefixed with %s,\n", name, name, prefix)
+		g.printf("// then validate it. Documents are merged in order, and Environment Variables take precedence over them.\n")
+		g.printf("func Load%s(docs ...map[string]interface{}) (*%s, error) {\n", name, name)
+		g.printf("config := &%s{}\n", name)
+		g.printf("config.setConfiguroDefaults()\n")
+		g.printf("for _, doc := range docs {\nif err := config.loadConfiguroDocument(doc, \"\"); err != nil {\nreturn nil, err\n}\n}\n")
+		g.printf("if err := config.loadConfiguroEnv(%q, \"\"); err != nil {\nreturn nil, err\n}\n", prefix)
+		g.printf("if errs := config.validateConfiguro(\"\"); len(errs) > 0 {\nreturn nil, errs\n}\n")
+		g.printf("return config, nil\n}\n\n")
+	}
+
+	for _, name := range g.structs {
+		if err := g.generateDefaults(name); err != nil {
+			return err
+		}
+		g.generateDocument(name)
+		g.generateEnv(name)
+		if err := g.generateValidate(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve the type of expr, struct types are added to the structs to generate.
+func (g *generator) resolve(expr ast.Expr) (*fieldType, error) {
+	goType := types.ExprString(expr)
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if basicTypes[e.Name] {
+			return &fieldType{kind: kindScalar, goType: e.Name, basic: e.Name}, nil
+		}
+		if e.Name == "any" {
+			return &fieldType{kind: kindAny, goType: e.Name}, nil
+		}
+		spec, found := g.specs[e.Name]
+		if !found || spec.TypeParams != nil {
+			break
+		}
+		if _, ok := spec.Type.(*ast.StructType); ok {
+			if err := g.addStruct(e.Name, spec); err != nil {
+				return nil, err
+			}
+			return &fieldType{kind: kindStruct, goType: e.Name}, nil
+		}
+		underlying, err := g.resolve(spec.Type)
+		if err != nil {
+			return nil, err
+		}
+		named := *underlying
+		named.goType = e.Name
+		return &named, nil
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "time" && e.Sel.Name == "Duration" {
+			return &fieldType{kind: kindDuration, goType: goType}, nil
+		}
+	case *ast.StarExpr:
+		elem, err := g.resolve(e.X)
+		if err != nil {
+			return nil, err
+		}
+		return &fieldType{kind: kindPtr, goType: goType, elem: elem}, nil
+	case *ast.ArrayType:
+		if e.Len != nil {
+			break
+		}
+		elem, err := g.resolve(e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &fieldType{kind: kindSlice, goType: goType, elem: elem}, nil
+	case *ast.MapType:
+		key, err := g.resolve(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		if key.kind != kindScalar || key.basic != "string" {
+			return nil, fmt.Errorf("map type %s is not supported, keys must be strings", goType)
+		}
+		elem, err := g.resolve(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &fieldType{kind: kindMap, goType: goType, key: key, elem: elem}, nil
+	case *ast.InterfaceType:
+		if e.Methods.NumFields() == 0 {
+			return &fieldType{kind: kindAny, goType: goType}, nil
+		}
+	}
+	return nil, fmt.Errorf("type %s is not supported", goType)
+}
+
+func (g *generator) addStruct(name string, spec *ast.TypeSpec) error {
+	if _, found := g.fields[name]; found || g.resolving[name] {
+		return nil
+	}
+	g.resolving[name] = true
+	g.structs = append(g.structs, name)
+
+	var fields []field
+	for _, astField := range spec.Type.(*ast.StructType).Fields.List {
+		var tag reflect.StructTag
+		if astField.Tag != nil {
+			value, err := strconv.Unquote(astField.Tag.Value)
+			if err != nil {
+				return err
+			}
+			tag = reflect.StructTag(value)
+		}
+
+		names := make([]string, 0, len(astField.Names))
+		for _, ident := range astField.Names {
+			names = append(names, ident.Name)
+		}
+		embedded := len(names) == 0
+		if embedded {
+			typeName := types.ExprString(astField.Type)
+			names = append(names, typeName[strings.LastIndex(typeName, ".")+1:])
+		}
+
+		tagName, tagOptions := tag.Get(g.tag), ""
+		if i := strings.Index(tagName, ","); i >= 0 {
+			tagName, tagOptions = tagName[:i], tagName[i+1:]
+		}
+		if tagName == "-" {
+			continue
+		}
+		squash := embedded && hasOption(tagOptions, "squash")
+
+		for _, name := range names {
+			if !ast.IsExported(name) && !squash {
+				continue
+			}
+
+			typ, err := g.resolve(astField.Type)
+			if err != nil {
+				return fmt.Errorf("field %s.%s: %v", spec.Name.Name, name, err)
+			}
+			if squash && structElem(typ) == nil {
+				return fmt.Errorf("field %s.%s: only structs can be squashed", spec.Name.Name, name)
+			}
+
+			key := tagName
+			if key == "" {
+				key = name
+			}
+			defaultTag, hasDefault := tag.Lookup("default")
+			fields = append(fields, field{
+				name:        name,
+				key:         strings.ToLower(key),
+				squash:      squash,
+				typ:         typ,
+				envNames:    envTagNames(tag.Get("env")),
+				defaultTag:  defaultTag,
+				hasDefault:  hasDefault,
+				validateTag: tag.Get(g.validateTag),
+			})
+		}
+	}
+
+	g.fields[name] = fields
+	delete(g.resolving, name)
+	return nil
+}
+
+func hasOption(options string, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func envTagNames(tagValue string) []string {
+	if tagValue == "-" {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(tagValue, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// envName return the name of the Environment Variable of key without the prefix (e.g `MAX__CONNS`).
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, "_", "__"))
+}
+
+// structElem return the struct type of a struct or a pointer to struct, or nil.
+func structElem(t *fieldType) *fieldType {
+	if t.kind == kindPtr {
+		t = t.elem
+	}
+	if t.kind == kindStruct {
+		return t
+	}
+	return nil
+}
+
+// hasDefaults return whether a struct or its nested structs have fields with defaults.
+func (g *generator) hasDefaults(name string, visited map[string]bool) bool {
+	if visited[name] {
+		return false
+	}
+	visited[name] = true
+	for _, f := range g.fields[name] {
+		if f.hasDefault {
+			return true
+		}
+		if elem := structElem(f.typ); elem != nil && g.hasDefaults(elem.goType, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// allocate write code allocating the value target points to if it's nil, structs are allocated with their defaults.
+func (g *generator) allocate(target string, t *fieldType) string {
+	if t.kind != kindPtr {
+		return target
+	}
+	g.printf("if %s == nil {\n%s = new(%s)\n", target, target, t.elem.goType)
+	g.setDefaults(target, t.elem)
+	g.printf("}\n")
+	return target
+}
+
+// setDefaults write code setting the defaults of target if it's a struct with defaults.
+func (g *generator) setDefaults(target string, t *fieldType) {
+	if t.kind == kindStruct && g.hasDefaults(t.goType, make(map[string]bool)) {
+		g.printf("%s.setConfiguroDefaults()\n", target)
+	}
+}
+
+// generateDefaults write the method setting the defaults of a struct. Pointers to structs are left nil, their defaults are
+// set when they're allocated, and the defaults of list and map elements are set when the elements are loaded.
+func (g *generator) generateDefaults(name string) error {
+	g.printf("func (c *%s) setConfiguroDefaults() {\n", name)
+	for _, f := range g.fields[name] {
+		target := "c." + f.name
+		if elem := structElem(f.typ); elem != nil {
+			if f.typ.kind != kindPtr {
+				g.setDefaults(target, elem)
+			} else if f.squash {
+				g.allocate(target, f.typ)
+			}
+			continue
+		}
+		if !f.hasDefault {
+			continue
+		}
+
+		typ := f.typ
+		if typ.kind == kindPtr {
+			typ = typ.elem
+		}
+		literal, err := defaultLiteral(typ, f.defaultTag)
+		if err != nil {
+			return fmt.Errorf("default of field %s.%s: %v", name, f.name, err)
+		}
+		if f.typ.kind == kindPtr {
+			g.printf("{\nv := %s(%s)\n%s = &v\n}\n", typ.goType, literal, target)
+			continue
+		}
+		g.printf("%s = %s\n", target, literal)
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// defaultLiteral return the Go literal of a `default` tag value, lists are JSON arrays or comma separated values.
+func defaultLiteral(t *fieldType, value string) (string, error) {
+	switch t.kind {
+	case kindDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(d), 10), nil
+	case kindScalar:
+		switch {
+		case t.basic == "string":
+			return strconv.Quote(value), nil
+		case t.basic == "bool":
+			b, err := strconv.ParseBool(value)
+			return strconv.FormatBool(b), err
+		case strings.HasPrefix(t.basic, "float"):
+			f, err := strconv.ParseFloat(value, 64)
+			return strconv.FormatFloat(f, 'g', -1, 64), err
+		case strings.HasPrefix(t.basic, "uint") || t.basic == "byte":
+			n, err := strconv.ParseUint(value, 0, 64)
+			return strconv.FormatUint(n, 10), err
+		default:
+			n, err := strconv.ParseInt(value, 0, 64)
+			return strconv.FormatInt(n, 10), err
+		}
+	case kindSlice:
+		var elems []string
+		var list []interface{}
+		if json.Unmarshal([]byte(value), &list) == nil {
+			for _, elem := range list {
+				elems = append(elems, fmt.Sprint(elem))
+			}
+		} else {
+			elems = strings.Split(value, ",")
+		}
+
+		literals := make([]string, len(elems))
+		for i, elem := range elems {
+			literal, err := defaultLiteral(t.elem, elem)
+			if err != nil {
+				return "", err
+			}
+			literals[i] = literal
+		}
+		return t.goType + "{" + strings.Join(literals, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("defaults of type %s are not supported", t.goType)
+}
+
+func (g *generator) generateDocument(name string) {
+	g.printf("func (c *%s) loadConfiguroDocument(doc map[string]interface{}, path string) error {\n", name)
+	for _, f := range g.fields[name] {
+		target := "c." + f.name
+		if f.squash {
+			g.printf("if err := %s.loadConfiguroDocument(doc, path); err != nil {\nreturn err\n}\n", g.allocate(target, f.typ))
+			continue
+		}
+		g.printf("if value, ok := loader.Lookup(doc, %q); ok {\n", f.key)
+		g.assign(target, f.typ, "value", fmt.Sprintf("loader.Join(path, %q)", f.key), 0)
+		g.printf("}\n")
+	}
+	g.printf("return nil\n}\n\n")
+}
+
+func (g *generator) generateEnv(name string) {
+	g.printf("func (c *%s) loadConfiguroEnv(prefix string, path string) error {\n", name)
+	for _, f := range g.fields[name] {
+		target := "c." + f.name
+		if f.squash {
+			g.printf("if err := %s.loadConfiguroEnv(prefix, path); err != nil {\nreturn err\n}\n", g.allocate(target, f.typ))
+			continue
+		}
+
+		envPrefix := fmt.Sprintf("loader.EnvName(prefix, %q)", envName(f.key))
+		key := fmt.Sprintf("loader.Join(path, %q)", f.key)
+		if elem := structElem(f.typ); elem != nil {
+			if f.typ.kind == kindPtr {
+				// Allocate pointers to structs only if one of their Environment Variables is set.
+				g.printf("if %s != nil || loader.EnvPrefixed(%s + \"_\") {\n", target, envPrefix)
+				g.allocate(target, f.typ)
+			}
+			g.printf("if err := %s.loadConfiguroEnv(%s, %s); err != nil {\nreturn err\n}\n", target, envPrefix, key)
+			if f.typ.kind == kindPtr {
+				g.printf("}\n")
+			}
+			continue
+		}
+
+		args := make([]string, 0, len(f.envNames)+1)
+		args = append(args, envPrefix)
+		for _, envName := range f.envNames {
+			args = append(args, strconv.Quote(envName))
+		}
+		g.printf("if value, ok := loader.FieldEnv(%s); ok {\n", strings.Join(args, ", "))
+		g.assign(target, f.typ, "value", key, 0)
+		g.printf("}\n")
+	}
+	g.printf("return nil\n}\n\n")
+}
+
+// assign write code converting the config value in the variable value and assigning it to target, key is the expression of its key.
+// The code declares variables, so it must be written in a new block.
+func (g *generator) assign(target string, t *fieldType, value string, key string, depth int) {
+	d := strconv.Itoa(depth)
+	fail := fmt.Sprintf("if err != nil {\nreturn &loader.FieldError{Key: %s, Err: err}\n}\n", key)
+
+	switch t.kind {
+	case kindAny:
+		g.printf("%s = %s\n", target, value)
+	case kindScalar, kindDuration:
+		g.printf("v%s, err := %s\n%s", d, converter(t, value), fail)
+		if t.goType == "time.Duration" {
+			// Already a time.Duration, the package doesn't import time.
+			g.printf("%s = v%s\n", target, d)
+		} else {
+			g.printf("%s = %s(v%s)\n", target, t.goType, d)
+		}
+	case kindStruct:
+		g.printf("m%s, err := loader.Map(%s)\n%s", d, value, fail)
+		g.printf("if err := %s.loadConfiguroDocument(m%s, %s); err != nil {\nreturn err\n}\n", target, d, key)
+	case kindPtr:
+		g.allocate(target, t)
+		g.assign("(*"+target+")", t.elem, value, key, depth)
+	case kindSlice:
+		g.printf("list%s, err := loader.List(%s)\n%s", d, value, fail)
+		g.printf("%s = make(%s, len(list%s))\n", target, t.goType, d)
+		g.printf("for i%s, elem%s := range list%s {\n", d, d, d)
+		g.setDefaults(fmt.Sprintf("%s[i%s]", target, d), t.elem)
+		g.assign(fmt.Sprintf("%s[i%s]", target, d), t.elem, "elem"+d, fmt.Sprintf("loader.Index(%s, i%s)", key, d), depth+1)
+		g.printf("}\n")
+	case kindMap:
+		g.printf("m%s, err := loader.Map(%s)\n%s", d, value, fail)
+		g.printf("if %s == nil {\n%s = make(%s, len(m%s))\n}\n", target, target, t.goType, d)
+		g.printf("for k%s, elem%s := range m%s {\n", d, d, d)
+		if t.elem.kind == kindStruct && g.hasDefaults(t.elem.goType, make(map[string]bool)) {
+			g.printf("e%s, found := %s[%s(k%s)]\nif !found {\ne%s.setConfiguroDefaults()\n}\n", d, target, t.key.goType, d, d)
+		} else {
+			g.printf("e%s := %s[%s(k%s)]\n", d, target, t.key.goType, d)
+		}
+		g.assign("e"+d, t.elem, "elem"+d, fmt.Sprintf("loader.Join(%s, k%s)", key, d), depth+1)
+		g.printf("%s[%s(k%s)] = e%s\n}\n", target, t.key.goType, d, d)
+	}
+}
+
+func converter(t *fieldType, value string) string {
+	if t.kind == kindDuration {
+		return fmt.Sprintf("loader.Duration(%s)", value)
+	}
+
+	basic := t.basic
+	switch basic {
+	case "byte":
+		basic = "uint8"
+	case "rune":
+		basic = "int32"
+	}
+	switch {
+	case basic == "string":
+		return fmt.Sprintf("loader.String(%s)", value)
+	case basic == "bool":
+		return fmt.Sprintf("loader.Bool(%s)", value)
+	case strings.HasPrefix(basic, "float"):
+		return fmt.Sprintf("loader.Float(%s, %s)", value, strings.TrimPrefix(basic, "float"))
+	case strings.HasPrefix(basic, "uint"):
+		return fmt.Sprintf("loader.Uint(%s, %s)", value, bitSize(strings.TrimPrefix(basic, "uint")))
+	default:
+		return fmt.Sprintf("loader.Int(%s, %s)", value, bitSize(strings.TrimPrefix(basic, "int")))
+	}
+}
+
+func bitSize(bits string) string {
+	if bits == "" {
+		return "0"
+	}
+	return bits
+}
+
+func (g *generator) generateValidate(name string) error {
+	g.printf("func (c *%s) validateConfiguro(path string) loader.Errors {\n", name)
+	g.printf("var errs loader.Errors\n")
+	for _, f := range g.fields[name] {
+		target := "c." + f.name
+		if f.squash {
+			if f.typ.kind == kindPtr {
+				g.printf("if %s != nil {\n", target)
+			}
+			g.printf("errs = append(errs, %s.validateConfiguro(path)...)\n", target)
+			if f.typ.kind == kindPtr {
+				g.printf("}\n")
+			}
+			continue
+		}
+
+		g.validating = name + "." + f.name
+		err := g.validate(target, f.typ, f.validateTag, fmt.Sprintf("loader.Join(path, %q)", f.key), 0)
+		if err != nil {
+			return fmt.Errorf("validation of field %s.%s: %v", name, f.name, err)
+		}
+	}
+	if g.validatable[name] {
+		g.printf("if err := c.Validate(); err != nil {\nerrs.Add(path, err)\n}\n")
+	}
+	g.printf("return errs\n}\n\n")
+	return nil
+}
+
+// validate write code validating target using the validation rules, nested structs are validated recursively.
+func (g *generator) validate(target string, t *fieldType, rules string, key string, depth int) error {
+	var fieldRules, diveRules []string
+	if rules != "" {
+		fieldRules = strings.Split(rules, ",")
+	}
+	for i, rule := range fieldRules {
+		if rule == "dive" {
+			fieldRules, diveRules = fieldRules[:i], fieldRules[i+1:]
+			if len(diveRules) == 0 {
+				diveRules = []string{}
+			}
+			break
+		}
+	}
+
+	required, omitempty := false, false
+	var checks []string
+	for _, rule := range fieldRules {
+		switch rule {
+		case "required":
+			required = true
+		case "omitempty":
+			omitempty = true
+		case "":
+		default:
+			checks = append(checks, rule)
+		}
+	}
+
+	if t.kind == kindPtr {
+		if required {
+			g.printf("if %s == nil {\nerrs.Addf(%s, \"is required\")\n} else {\n", target, key)
+		} else {
+			g.printf("if %s != nil {\n", target)
+		}
+		rules := strings.Join(checks, ",")
+		if diveRules != nil {
+			rules = strings.Join(append(append(checks, "dive"), diveRules...), ",")
+		}
+		if err := g.validate("(*"+target+")", t.elem, rules, key, depth); err != nil {
+			return err
+		}
+		g.printf("}\n")
+		return nil
+	}
+
+	zero := zeroCheck(target, t)
+	if required && zero != "" {
+		// Structs are never zero for required, as go-playground/validator doesn't check them.
+		g.printf("if %s {\nerrs.Addf(%s, \"is required\")\n}\n", zero, key)
+	}
+	if omitempty && zero != "" && len(checks) > 0 {
+		g.printf("if !(%s) {\n", zero)
+	}
+	for _, rule := range checks {
+		if err := g.check(target, t, rule, key); err != nil {
+			return err
+		}
+	}
+	if omitempty && zero != "" && len(checks) > 0 {
+		g.printf("}\n")
+	}
+
+	if t.kind == kindStruct {
+		g.printf("errs = append(errs, %s.validateConfiguro(%s)...)\n", target, key)
+		return nil
+	}
+
+	// Validate elements that have rules or are structs.
+	if (t.kind == kindSlice || t.kind == kindMap) && (hasChecks(diveRules) || structElem(t.elem) != nil) {
+		d := strconv.Itoa(depth)
+		if t.kind == kindSlice {
+			g.printf("for i%s := range %s {\n", d, target)
+			if err := g.validate(fmt.Sprintf("%s[i%s]", target, d), t.elem, strings.Join(diveRules, ","), fmt.Sprintf("loader.Index(%s, i%s)", key, d), depth+1); err != nil {
+				return err
+			}
+		} else {
+			g.printf("for k%s, e%s := range %s {\n", d, d, target)
+			if err := g.validate("e"+d, t.elem, strings.Join(diveRules, ","), fmt.Sprintf("loader.Join(%s, string(k%s))", key, d), depth+1); err != nil {
+				return err
+			}
+		}
+		g.printf("}\n")
+	}
+	return nil
+}
+
+// hasChecks return whether rules validate values, omitempty alone doesn't.
+func hasChecks(rules []string) bool {
+	for _, rule := range rules {
+		if rule != "omitempty" && rule != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// zeroCheck return the expression checking if target is zero, or "" for structs which are never zero.
+func zeroCheck(target string, t *fieldType) string {
+	switch t.kind {
+	case kindSlice, kindMap:
+		return fmt.Sprintf("len(%s) == 0", target)
+	case kindAny:
+		return fmt.Sprintf("%s == nil", target)
+	case kindDuration:
+		return fmt.Sprintf("%s == 0", target)
+	case kindScalar:
+		switch {
+		case t.basic == "string":
+			return fmt.Sprintf("%s == \"\"", target)
+		case t.basic == "bool":
+			return fmt.Sprintf("!%s", target)
+		default:
+			return fmt.Sprintf("%s == 0", target)
+		}
+	}
+	return ""
+}
+
+var formatChecks = map[string]string{
+	"url":              "IsURL",
+	"uri":              "IsURL",
+	"email":            "IsEmail",
+	"ip":               "IsIP",
+	"ipv4":             "IsIPv4",
+	"ipv6":             "IsIPv6",
+	"hostname":         "IsHostnameRFC952",
+	"hostname_rfc1123": "IsHostname",
+	"uuid":             "IsUUID",
+}
+
+// check write the code of a validation rule.
+func (g *generator) check(target string, t *fieldType, rule string, key string) error {
+	name, param := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, param = rule[:i], rule[i+1:]
+	}
+
+	fail := func(condition string, message string) {
+		g.printf("if %s {\nerrs.Addf(%s, %s)\n}\n", condition, key, strconv.Quote(strings.ReplaceAll(message, "%", "%%")))
+	}
+
+	isString := t.kind == kindScalar && t.basic == "string"
+	isNumber := t.kind == kindDuration || t.kind == kindScalar && t.basic != "string" && t.basic != "bool"
+	isList := t.kind == kindSlice || t.kind == kindMap
+
+	unsupported := fmt.Errorf("rule %s is not supported for type %s", rule, t.goType)
+
+	if function, ok := formatChecks[name]; ok {
+		if !isString {
+			return unsupported
+		}
+		fail(fmt.Sprintf("!loader.%s(string(%s))", function, target), "must be a valid "+name)
+		return nil
+	}
+
+	if name == "oneof" {
+		values := strings.Fields(param)
+		switch {
+		case isString:
+			quoted := make([]string, len(values))
+			for i, value := range values {
+				quoted[i] = strconv.Quote(value)
+			}
+			fail(fmt.Sprintf("!loader.OneOf(string(%s), %s)", target, strings.Join(quoted, ", ")), "must be one of: "+strings.Join(values, ", "))
+		case isNumber:
+			conditions := make([]string, len(values))
+			for i, value := range values {
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					return fmt.Errorf("rule %s: %s is not a number", rule, value)
+				}
+				conditions[i] = fmt.Sprintf("%s == %s", target, value)
+			}
+			fail("!("+strings.Join(conditions, " || ")+")", "must be one of: "+strings.Join(values, ", "))
+		default:
+			return unsupported
+		}
+		return nil
+	}
+
+	operators := map[string]string{"min": "<", "gte": "<", "max": ">", "lte": ">", "gt": "<=", "lt": ">=", "len": "!="}
+	operator, ok := operators[name]
+	if !ok {
+		g.warnf("validation of field %s: rule %s is not supported and is skipped", g.validating, rule)
+		return nil
+	}
+	bounds := map[string]string{"min": ">=", "gte": ">=", "max": "<=", "lte": "<=", "gt": ">", "lt": "<", "len": "="}
+
+	switch {
+	case t.kind == kindDuration:
+		// go-playground/validator compares durations to nanoseconds, durations (e.g `1s`) are accepted too.
+		d, err := time.ParseDuration(param)
+		if n, intErr := strconv.ParseInt(param, 10, 64); intErr == nil {
+			d, err = time.Duration(n), nil
+		}
+		if err != nil {
+			return fmt.Errorf("rule %s: %v", rule, err)
+		}
+		fail(fmt.Sprintf("%s %s %d", target, operator, int64(d)), fmt.Sprintf("must be %s %s", bounds[name], param))
+	case isNumber:
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("rule %s: %s is not a number", rule, param)
+		}
+		fail(fmt.Sprintf("%s %s %s", target, operator, param), fmt.Sprintf("must be %s %s", bounds[name], param))
+	case isString, isList:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Errorf("rule %s: %s is not an integer", rule, param)
+		}
+		length := fmt.Sprintf("len(%s)", target)
+		message := fmt.Sprintf("must have %s %d items", bounds[name], n)
+		if isString {
+			length = fmt.Sprintf("loader.Len(string(%s))", target)
+			message = fmt.Sprintf("length must be %s %d", bounds[name], n)
+		}
+		fail(fmt.Sprintf("%s %s %d", length, operator, n), message)
+	default:
+		return unsupported
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// result of loading a config file by the fixture program, using the generated loader or Config.Load.
+type result struct {
+	Config map[string]interface{} `json:"config"`
+	Err    string                 `json:"err"`
+}
+
+// buildFixture generate the loader of the fixture package in testdata, and build the fixture program.
+// The package is generated in testdata so the generated code can import the loader package of this module.
+func buildFixture(t *testing.T) string {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir, err := ioutil.TempDir("testdata", "fixture-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for _, name := range []string{"config.go", "main.go"} {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "fixture", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, name), data, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warnings, err := run(dir, []string{"Config"}, filepath.Join(dir, "config_configuro.go"), "GENTEST", "config", "validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedWarnings := []string{"validation of field Database.User: rule alphanum is not supported and is skipped"}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Fatalf("Warnings don't equal expected. warnings: %q, expected: %q", warnings, expectedWarnings)
+	}
+
+	bin := filepath.Join(dir, "fixture")
+	output, err := exec.Command(goBin, "build", "-o", bin, "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("error building the generated loader: %v\n%s", err, output)
+	}
+	return bin
+}
+
+func TestGeneratedLoader(t *testing.T) {
+	bin := buildFixture(t)
+
+	configFile := filepath.Join(filepath.Dir(bin), "config.yml")
+	err := ioutil.WriteFile(configFile, []byte(`
+name: app
+ratio: 0.25
+database:
+  hosts:
+    - addr: db1
+    - addr: db2
+      port: 6432
+  replicas:
+    east:
+      addr: db3
+cache:
+  addr: redis
+labels:
+  team: core
+extra:
+  a: 1
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  string // the config file, if not the default one
+		env     []string
+		wantErr bool
+	}{
+		{name: "file only"},
+		{
+			name: "env vars",
+			env: []string{
+				"DB_PASSWORD=secret", "GENTEST_BACKUP_ADDR=backup", "GENTEST_MAX__CONNS=10", "GENTEST_DEBUG=true",
+				`GENTEST_DATABASE_HOSTS=[{"addr": "db4"}]`, `GENTEST_TAGS=["x","y"]`, "GENTEST_REGION=us",
+				"GENTEST_DATABASE_TLS_ENABLED=false", "GENTEST_DATABASE_TIMEOUT=2s", "GENTEST_EMAIL=admin@example.com",
+			},
+		},
+		{name: "overflow", env: []string{"GENTEST_WORKERS=200"}, wantErr: true},
+		{name: "oneof", env: []string{"GENTEST_LEVEL=trace"}, wantErr: true},
+		{name: "min items", env: []string{"GENTEST_DATABASE_HOSTS=[]"}, wantErr: true},
+		{name: "email", env: []string{"GENTEST_EMAIL=admin"}, wantErr: true},
+		{name: "hostname", env: []string{"GENTEST_CACHE_ADDR=-redis"}, wantErr: true},
+		{name: "duration min", env: []string{"GENTEST_DATABASE_TIMEOUT=10ms"}, wantErr: true},
+		{name: "max", env: []string{"GENTEST_CACHE_PORT=70000"}, wantErr: true},
+		{name: "empty env var", env: []string{"GENTEST_NAME=", "GENTEST_CACHE_PORT="}},
+		{name: "required", config: "database:\n  hosts: [{addr: db1}]\nextra: 1\n", wantErr: true},
+		{name: "validatable", env: []string{"GENTEST_NAME=invalid"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := configFile
+			if test.config != "" {
+				path = filepath.Join(filepath.Dir(bin), "test.yml")
+				err := ioutil.WriteFile(path, []byte(test.config), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cmd := exec.Command(bin, path)
+			for _, env := range os.Environ() {
+				if !strings.HasPrefix(env, "GENTEST_") && !strings.HasPrefix(env, "DB_PASSWORD=") {
+					cmd.Env = append(cmd.Env, env)
+				}
+			}
+			cmd.Env = append(cmd.Env, test.env...)
+
+			output, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("error running the generated loader: %v", err)
+			}
+
+			var results map[string]result
+			err = json.Unmarshal(output, &results)
+			if err != nil {
+				t.Fatal(err)
+			}
+			generated, configuro := results["generated"], results["configuro"]
+
+			if (generated.Err != "") != test.wantErr || (configuro.Err != "") != test.wantErr {
+				t.Fatalf("expected error: %v, generated loader error: %q, Config.Load error: %q", test.wantErr, generated.Err, configuro.Err)
+			}
+			if !reflect.DeepEqual(generated.Config, configuro.Config) {
+				t.Fatalf("Generated loader doesn't load the same config as Config.Load. generated: %v, Config.Load: %v", generated.Config, configuro.Config)
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "unsupported type",
+			src:      "type Config struct {\n\tC chan int\n}\n",
+			expected: "field Config.C: type chan int is not supported",
+		},
+		{
+			name:     "unsupported map key",
+			src:      "type Config struct {\n\tM map[int]string\n}\n",
+			expected: "map type map[int]string is not supported, keys must be strings",
+		},
+		{
+			name:     "invalid rule parameter",
+			src:      "type Config struct {\n\tPort int `validate:\"min=a\"`\n}\n",
+			expected: "validation of field Config.Port: rule min=a: a is not a number",
+		},
+		{
+			name:     "rule of another type",
+			src:      "type Config struct {\n\tPort int `validate:\"email\"`\n}\n",
+			expected: "validation of field Config.Port: rule email is not supported for type int",
+		},
+		{
+			name:     "not a struct",
+			src:      "type Config string\n",
+			expected: "type Config is not a struct",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := ioutil.WriteFile(filepath.Join(dir, "config.go"), []byte("package config\n\n"+test.src), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = run(dir, []string{"Config"}, filepath.Join(dir, "config_configuro.go"), "CONFIG", "config", "validate")
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected error %q, got: %v", test.expected, err)
+			}
+		})
+	}
+}
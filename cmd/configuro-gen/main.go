@@ -0,0 +1,83 @@
+//Command configuro-gen Generate loaders of config structs that don't use reflection, for programs with startup time budgets.
+//
+// Add a go:generate directive to the package declaring the config struct:
+//
+//	//go:generate go run github.com/sherifabdlnaby/configuro/cmd/configuro-gen -type Config -prefix CONFIG
+//
+// For every type it generates a `LoadConfig(docs ...map[string]interface{}) (*Config, error)` function that sets the
+// `default` tags, loads the config documents (e.g read using loader.ReadFile) and the Environment Variables, then validates
+// the `validate` tags and calls Validate() of types implementing configuro.Validatable. Validation rules that aren't
+// supported are skipped with a warning.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated names of the config struct types (required)")
+	prefix := flag.String("prefix", "CONFIG", "prefix of the Environment Variables")
+	output := flag.String("output", "", "output file (default: <type>_configuro.go)")
+	tag := flag.String("tag", "config", "struct tag of the config keys")
+	validateTag := flag.String("validate-tag", "validate", "struct tag of the validation rules")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: configuro-gen -type Config [-prefix CONFIG] [-output file] [DIR]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+
+	types := strings.Split(*typeNames, ",")
+	if *output == "" {
+		*output = strings.ToLower(types[0]) + "_configuro.go"
+	}
+	if !filepath.IsAbs(*output) {
+		*output = filepath.Join(dir, *output)
+	}
+
+	warnings, err := run(dir, types, *output, *prefix, *tag, *validateTag)
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "configuro-gen: warning:", warning)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "configuro-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generate the loaders of types in the package in dir to output, and return the warnings of skipped validation rules.
+func run(dir string, types []string, output string, prefix string, tag string, validateTag string) ([]string, error) {
+	g, err := newGenerator(dir, output, tag, validateTag)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.generate(types, prefix)
+	if err != nil {
+		return g.warnings, err
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		// Write the unformatted source to debug it.
+		_ = ioutil.WriteFile(output, g.buf.Bytes(), 0644)
+		return g.warnings, fmt.Errorf("error formatting generated code: %v", err)
+	}
+
+	return g.warnings, ioutil.WriteFile(output, src, 0644)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+type Level string
+
+type Host struct {
+	Addr string `config:"addr" validate:"required,hostname"`
+	Port int    `config:"port" default:"5432" validate:"min=1,max=65535"`
+}
+
+type TLS struct {
+	Enabled bool   `config:"enabled" default:"true"`
+	Cert    string `config:"cert"`
+}
+
+type Database struct {
+	Hosts    []Host          `config:"hosts" validate:"min=1,dive"`
+	Replicas map[string]Host `config:"replicas"`
+	User     string          `config:"user" default:"admin" validate:"alphanum"`
+	Password string          `config:"password" env:"DB_PASSWORD"`
+	Timeout  time.Duration   `config:"timeout" default:"5s" validate:"min=1000000000"`
+	TLS      TLS             `config:"tls"`
+}
+
+type Common struct {
+	Region string `config:"region" default:"eu"`
+}
+
+type Config struct {
+	Common   `config:",squash"`
+	Name     string            `config:"name" validate:"required"`
+	Level    Level             `config:"level" default:"info" validate:"oneof=debug info error"`
+	Workers  int8              `config:"workers" default:"4" validate:"min=1"`
+	Ratio    float64           `config:"ratio"`
+	MaxConns uint16            `config:"max_conns"`
+	Debug    bool              `config:"debug"`
+	Email    string            `config:"email" validate:"omitempty,email"`
+	Tags     []string          `config:"tags" default:"a,b"`
+	Labels   map[string]string `config:"labels"`
+	Database Database          `config:"database" validate:"required"`
+	Cache    *Host             `config:"cache"`
+	Backup   *Host             `config:"backup"`
+	Extra    interface{}       `config:"extra"`
+}
+
+func (c Config) Validate() error {
+	if c.Name == "invalid" {
+		return errors.New("name is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Load the config file in the first argument using the generated loader and Config.Load, and print both as JSON.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sherifabdlnaby/configuro"
+	"github.com/sherifabdlnaby/configuro/loader"
+)
+
+type result struct {
+	Config *Config `json:"config"`
+	Err    string  `json:"err,omitempty"`
+}
+
+func main() {
+	var generated, reflected result
+
+	doc, err := loader.ReadFile(os.Args[1])
+	if err == nil {
+		generated.Config, err = LoadConfig(doc)
+	}
+	if err != nil {
+		generated.Err = err.Error()
+	}
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("GENTEST"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(os.Args[1], true),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithoutWarningLogger(),
+	)
+	if err == nil {
+		reflected.Config = &Config{}
+		err = configLoader.Load(reflected.Config)
+	}
+	if err == nil {
+		err = configLoader.Validate(reflected.Config)
+	}
+	if err != nil {
+		reflected.Config, reflected.Err = nil, err.Error()
+	}
+
+	out, _ := json.Marshal(map[string]result{"generated": generated, "configuro": reflected})
+	fmt.Println(string(out))
+}
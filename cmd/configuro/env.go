@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sherifabdlnaby/configuro"
+)
+
+// envVars return `NAME=value` lines setting every key of doc, in the format of .env files.
+// Lists are JSON encoded as a whole, and empty maps are set to `{}`.
+func envVars(config *configuro.Config, doc map[string]interface{}, delimiter string) []string {
+	var lines []string
+	var walk func(value interface{}, key string)
+	walk = func(value interface{}, key string) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if len(v) == 0 && key != "" {
+				lines = append(lines, config.EnvVarName(key)+"={}")
+			}
+			for k, elem := range v {
+				if key != "" {
+					k = key + delimiter + k
+				}
+				walk(elem, k)
+			}
+		case []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				data = []byte(fmt.Sprint(v))
+			}
+			lines = append(lines, config.EnvVarName(key)+"="+quoteEnvValue(string(data)))
+		default:
+			lines = append(lines, config.EnvVarName(key)+"="+quoteEnvValue(envValue(v)))
+		}
+	}
+	walk(doc, "")
+
+	sort.Strings(lines)
+	return lines
+}
+
+func envValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+// quoteEnvValue quote values that aren't safe unquoted in shells and .env files, single quotes are used unless the value has one.
+func quoteEnvValue(value string) string {
+	safe := value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,:/@+%", r))
+	}) < 0
+	if safe || value == "" {
+		return value
+	}
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	return strconv.Quote(value)
+}
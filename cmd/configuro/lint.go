@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// duplicateKeys return a problem for every key defined more than once in the same map of a config file.
+// Keys are compared case insensitively as configuro loads them. Line numbers are reported for YAML and JSON files.
+func duplicateKeys(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	report := func(key string, line int, firstLine int) {
+		if line == 0 {
+			problems = append(problems, fmt.Sprintf("%s: duplicate key %q", path, key))
+			return
+		}
+		problems = append(problems, fmt.Sprintf("%s:%d: duplicate key %q (first defined at line %d)", path, line, key, firstLine))
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		var node yamlv3.Node
+		if yamlv3.Unmarshal(data, &node) == nil {
+			yamlDuplicateKeys(&node, "", report)
+			return problems, nil
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		if jsonDuplicateKeys(decoder, data, "", report) == nil {
+			return problems, nil
+		}
+	}
+
+	problems = nil
+
+	// Other formats (or files that only parse after rendering templates) are checked after parsing, where keys that are
+	// exactly the same are already merged by the parser.
+	config, err := newConfig()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := config.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	foldDuplicateKeys(doc, "", report)
+	return problems, nil
+}
+
+func joinKey(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func yamlDuplicateKeys(node *yamlv3.Node, path string, report func(key string, line int, firstLine int)) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, child := range node.Content {
+			yamlDuplicateKeys(child, path, report)
+		}
+	case yamlv3.SequenceNode:
+		for i, child := range node.Content {
+			yamlDuplicateKeys(child, joinKey(path, strconv.Itoa(i)), report)
+		}
+	case yamlv3.MappingNode:
+		seen := make(map[string]int)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			keyPath := joinKey(path, key.Value)
+			if firstLine, found := seen[strings.ToLower(key.Value)]; found {
+				report(keyPath, key.Line, firstLine)
+			} else {
+				seen[strings.ToLower(key.Value)] = key.Line
+			}
+			yamlDuplicateKeys(value, keyPath, report)
+		}
+	}
+}
+
+func jsonDuplicateKeys(decoder *json.Decoder, data []byte, path string, report func(key string, line int, firstLine int)) error {
+	line := func() int {
+		return bytes.Count(data[:decoder.InputOffset()], []byte("\n")) + 1
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		seen := make(map[string]int)
+		for decoder.More() {
+			token, err = decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := token.(string)
+			keyPath := joinKey(path, key)
+			if firstLine, found := seen[strings.ToLower(key)]; found {
+				report(keyPath, line(), firstLine)
+			} else {
+				seen[strings.ToLower(key)] = line()
+			}
+			err = jsonDuplicateKeys(decoder, data, keyPath, report)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			err = jsonDuplicateKeys(decoder, data, joinKey(path, strconv.Itoa(i)), report)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	}
+	return err
+}
+
+func foldDuplicateKeys(value interface{}, path string, report func(key string, line int, firstLine int)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		seen := make(map[string]bool)
+		for _, key := range keys {
+			if seen[strings.ToLower(key)] {
+				report(joinKey(path, key), 0, 0)
+			}
+			seen[strings.ToLower(key)] = true
+			foldDuplicateKeys(v[key], joinKey(path, key), report)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			foldDuplicateKeys(elem, joinKey(path, strconv.Itoa(i)), report)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+//Command configuro lint, convert, merge, and print the Environment Variables of config files without a Go struct.
+//
+//	configuro lint [-schema schema.json] FILE...
+//	configuro convert [-format yaml|json|toml] IN [OUT]
+//	configuro env [-prefix CONFIG] [-delimiter .] FILE
+//	configuro merge [-format yaml|json|toml] FILE...
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sherifabdlnaby/configuro"
+)
+
+const usage = `configuro inspects config files without a Go struct.
+
+Usage:
+  configuro lint [-schema schema.json] FILE...       Check syntax, duplicate keys, and the schema of config files.
+  configuro convert [-format yaml|json|toml] IN [OUT] Convert a config file to the format of OUT (or print it in -format).
+  configuro env [-prefix CONFIG] [-delimiter .] FILE Print the Environment Variables equivalent to a config file.
+  configuro merge [-format yaml|json|toml] FILE...   Print config files merged as they're merged when loading, later files take precedence.
+`
+
+// errProblems is returned when lint found problems it already printed.
+var errProblems = errors.New("problems found")
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "lint":
+		err = lint(os.Args[2:])
+	case "convert":
+		err = convert(os.Args[2:])
+	case "env":
+		err = env(os.Args[2:])
+	case "merge":
+		err = merge(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		if err != errProblems {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// newConfig create a Config that only reads the files it's given.
+func newConfig(opts ...configuro.ConfigOptions) (*configuro.Config, error) {
+	return configuro.NewConfig(append([]configuro.ConfigOptions{
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithoutLoadFromConfigFile(),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithoutWarningLogger(),
+	}, opts...)...)
+}
+
+func newFlagSet(name string, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: configuro %s %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseArgs parse flags set anywhere in args (before or after the positional arguments), and return the positional arguments.
+// Arguments after "--" are all positional.
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		_ = flags.Parse(args)
+		rest := flags.Args()
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...)
+		}
+		if len(rest) == 0 {
+			return positional
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func lint(args []string) error {
+	flags := newFlagSet("lint", "[-schema schema.json] FILE...")
+	schemaPath := flags.String("schema", "", "JSON Schema to validate the config files against")
+	paths := parseArgs(flags, args)
+	if len(paths) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var opts []configuro.ConfigOptions
+	if *schemaPath != "" {
+		schema, err := ioutil.ReadFile(*schemaPath)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, configuro.WithSchema(schema))
+	}
+	config, err := newConfig(opts...)
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for _, path := range paths {
+		problems := lintFile(config, path)
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		if len(problems) > 0 {
+			failed = true
+			continue
+		}
+		fmt.Printf("%s: ok\n", path)
+	}
+
+	if failed {
+		return errProblems
+	}
+	return nil
+}
+
+func lintFile(config *configuro.Config, path string) []string {
+	doc, err := config.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+
+	problems, err := duplicateKeys(path)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+
+	err = config.ValidateDocument(doc)
+	var validationErrs configuro.ErrValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, err := range validationErrs.Errors() {
+			problems = append(problems, fmt.Sprintf("%s: %v", path, err))
+		}
+	} else if err != nil {
+		problems = append(problems, fmt.Sprintf("%s: %v", path, err))
+	}
+
+	return problems
+}
+
+func convert(args []string) error {
+	flags := newFlagSet("convert", "[-format yaml|json|toml] IN [OUT]")
+	format := flags.String("format", "yaml", "format to print in if OUT is not set")
+	paths := parseArgs(flags, args)
+	if len(paths) != 1 && len(paths) != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config, err := newConfig()
+	if err != nil {
+		return err
+	}
+
+	doc, err := config.ReadFile(paths[0])
+	if err != nil {
+		return err
+	}
+
+	if len(paths) == 2 {
+		return config.WriteFile(paths[1], doc)
+	}
+	return printDocument(doc, *format)
+}
+
+func env(args []string) error {
+	flags := newFlagSet("env", "[-prefix CONFIG] [-delimiter .] FILE")
+	prefix := flags.String("prefix", "CONFIG", "prefix of the Environment Variables")
+	delimiter := flags.String("delimiter", ".", "key delimiter")
+	paths := parseArgs(flags, args)
+	if len(paths) != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config, err := newConfig(configuro.WithLoadFromEnvVars(*prefix), configuro.KeyDelimiter(*delimiter))
+	if err != nil {
+		return err
+	}
+
+	doc, err := config.ReadFile(paths[0])
+	if err != nil {
+		return err
+	}
+
+	// Keys that differ only in case set the same Environment Variable, they're merged the same way merge does.
+	for _, envVar := range envVars(config, configuro.MergeDocuments(doc), *delimiter) {
+		fmt.Println(envVar)
+	}
+	return nil
+}
+
+func merge(args []string) error {
+	flags := newFlagSet("merge", "[-format yaml|json|toml] FILE...")
+	format := flags.String("format", "", "format to print in (default: the format of the first file, or yaml)")
+	paths := parseArgs(flags, args)
+	if len(paths) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config, err := newConfig()
+	if err != nil {
+		return err
+	}
+
+	docs := make([]map[string]interface{}, len(paths))
+	for i, path := range paths {
+		docs[i], err = config.ReadFile(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *format == "" {
+		*format = "yaml"
+		switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(paths[0]), ".")); ext {
+		case "yml", "json", "toml":
+			*format = ext
+		}
+	}
+	return printDocument(configuro.MergeDocuments(docs...), *format)
+}
+
+func printDocument(doc map[string]interface{}, format string) error {
+	data, err := configuro.MarshalDocument(doc, format)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
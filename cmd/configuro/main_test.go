@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMain run main with the arguments after "--" when started by runMain.
+func TestMain(m *testing.M) {
+	if os.Getenv("CONFIGURO_TEST_MAIN") == "1" {
+		for i, arg := range os.Args {
+			if arg == "--" {
+				os.Args = append([]string{"configuro"}, os.Args[i+1:]...)
+				break
+			}
+		}
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain run the configuro command with args in a subprocess, and return its stdout, stderr, and exit code.
+func runMain(t *testing.T, args ...string) (string, string, int) {
+	cmd := exec.Command(os.Args[0], append([]string{"-test.run=^$", "--"}, args...)...)
+	cmd.Env = append(os.Environ(), "CONFIGURO_TEST_MAIN=1")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	code := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return stdout.String(), stderr.String(), code
+}
+
+// writeFiles write files (name -> content) to a temporary directory and return its path.
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "configuro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"dup.yml":    "port: 80\nPort: 81\ndb:\n  host: a\n  hosts:\n    - {name: a, NAME: b}\n  host: b\n",
+		"dup.json":   "{\n  \"port\": 80,\n  \"db\": {\n    \"host\": \"a\",\n    \"HOST\": \"b\"\n  },\n  \"port\": 81\n}\n",
+		"dup.toml":   "Port = 1\nport = 2\n\n[db]\nhost = \"a\"\n",
+		"unique.yml": "port: 80\ndb:\n  host: a\n",
+	})
+
+	tests := []struct {
+		file     string
+		expected []string
+	}{
+		{
+			file: "dup.yml",
+			expected: []string{
+				`dup.yml:2: duplicate key "Port" (first defined at line 1)`,
+				`dup.yml:6: duplicate key "db.hosts.0.NAME" (first defined at line 6)`,
+				`dup.yml:7: duplicate key "db.host" (first defined at line 4)`,
+			},
+		},
+		{
+			file: "dup.json",
+			expected: []string{
+				`dup.json:5: duplicate key "db.HOST" (first defined at line 4)`,
+				`dup.json:7: duplicate key "port" (first defined at line 2)`,
+			},
+		},
+		{
+			// TOML files are checked after parsing, so duplicates are found case insensitively without line numbers.
+			file:     "dup.toml",
+			expected: []string{`dup.toml: duplicate key "port"`},
+		},
+		{
+			file: "unique.yml",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			path := filepath.Join(dir, test.file)
+			problems, err := duplicateKeys(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range problems {
+				problems[i] = strings.TrimPrefix(problems[i], dir+string(filepath.Separator))
+			}
+			if !reflect.DeepEqual(problems, test.expected) {
+				t.Fatalf("Duplicate keys don't equal expected. found: %q, expected: %q", problems, test.expected)
+			}
+		})
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"ok.yml":      "port: 80\n",
+		"dup.yml":     "port: 80\nport: 81\n",
+		"invalid.yml": "port: [80\n",
+	})
+
+	config, err := newConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if problems := lintFile(config, filepath.Join(dir, "ok.yml")); len(problems) != 0 {
+		t.Fatalf("expected no problems, found: %q", problems)
+	}
+	if problems := lintFile(config, filepath.Join(dir, "dup.yml")); len(problems) != 1 || !strings.Contains(problems[0], `duplicate key "port"`) {
+		t.Fatalf("expected a duplicate key problem, found: %q", problems)
+	}
+	if problems := lintFile(config, filepath.Join(dir, "invalid.yml")); len(problems) != 1 || !strings.Contains(problems[0], "error parsing config file") {
+		t.Fatalf("expected a parse error problem, found: %q", problems)
+	}
+}
+
+func TestQuoteEnvValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "", expected: ""},
+		{value: "localhost:5432", expected: "localhost:5432"},
+		{value: "https://example.com/a,b@c+d%e", expected: "https://example.com/a,b@c+d%e"},
+		{value: "a b", expected: "'a b'"},
+		{value: "$HOME", expected: "'$HOME'"},
+		{value: "it's", expected: `"it's"`},
+		{value: "line\nbreak", expected: "'line\nbreak'"},
+		{value: "it's\n", expected: `"it's\n"`},
+	}
+
+	for _, test := range tests {
+		if quoted := quoteEnvValue(test.value); quoted != test.expected {
+			t.Errorf("quoteEnvValue(%q) = %s, expected: %s", test.value, quoted, test.expected)
+		}
+	}
+}
+
+func TestEnvVars(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yml": `
+name: "it's"
+port: 80
+list: [1, "a b"]
+empty: {}
+nested:
+  key: "x y"
+  nothing:
+`,
+	})
+
+	tests := []struct {
+		prefix    string
+		delimiter string
+		expected  []string
+	}{
+		{
+			prefix:    "CONFIG",
+			delimiter: ".",
+			expected: []string{
+				"CONFIG_EMPTY={}",
+				`CONFIG_LIST='[1,"a b"]'`,
+				`CONFIG_NAME="it's"`,
+				"CONFIG_NESTED_KEY='x y'",
+				"CONFIG_NESTED_NOTHING=",
+				"CONFIG_PORT=80",
+			},
+		},
+		{
+			// Delimiters that are underscores are escaped as double underscores.
+			prefix:    "APP",
+			delimiter: "_",
+			expected: []string{
+				"APP_EMPTY={}",
+				`APP_LIST='[1,"a b"]'`,
+				`APP_NAME="it's"`,
+				"APP_NESTED__KEY='x y'",
+				"APP_NESTED__NOTHING=",
+				"APP_PORT=80",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.prefix, func(t *testing.T) {
+			stdout, stderr, code := runMain(t, "env", "-prefix", test.prefix, "-delimiter", test.delimiter, filepath.Join(dir, "config.yml"))
+			if code != 0 {
+				t.Fatalf("expected exit code 0, got: %d, stderr: %s", code, stderr)
+			}
+			lines := strings.Split(strings.TrimSpace(stdout), "\n")
+			if !reflect.DeepEqual(lines, test.expected) {
+				t.Fatalf("Environment Variables don't equal expected. printed: %q, expected: %q", lines, test.expected)
+			}
+		})
+	}
+}
+
+func TestEnvFlagsAfterFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yml": "port: 80\nPort: 81\nname: app\n",
+	})
+
+	stdout, stderr, code := runMain(t, "env", filepath.Join(dir, "config.yml"), "--prefix", "APP")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got: %d, stderr: %s", code, stderr)
+	}
+
+	// Keys that differ only in case are printed once, with the value merge uses.
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	expected := []string{"APP_NAME=app", "APP_PORT=80"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Environment Variables don't equal expected. printed: %q, expected: %q", lines, expected)
+	}
+
+	stdout, stderr, code = runMain(t, "merge", filepath.Join(dir, "config.yml"), "-format", "json")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got: %d, stderr: %s", code, stderr)
+	}
+	if expected := "{\n  \"name\": \"app\",\n  \"port\": 80\n}\n"; stdout != expected {
+		t.Fatalf("Merged config doesn't equal expected. printed:\n%s\nexpected:\n%s", stdout, expected)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"base.json": `{"port": 80, "db": {"host": "a", "port": 5432}}`,
+		"prod.yml":  "db:\n  host: b\n",
+		"base.toml": "port = 80\n",
+		"base.yaml": "port: 80\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "format of the first file",
+			args:     []string{"base.json", "prod.yml"},
+			expected: "{\n  \"db\": {\n    \"host\": \"b\",\n    \"port\": 5432\n  },\n  \"port\": 80\n}\n",
+		},
+		{
+			name:     "format flag",
+			args:     []string{"-format", "yaml", "base.json", "prod.yml"},
+			expected: "db:\n  host: b\n  port: 5432\nport: 80\n",
+		},
+		{
+			name:     "toml",
+			args:     []string{"base.toml"},
+			expected: "port = 80\n",
+		},
+		{
+			name:     "yaml is the default",
+			args:     []string{"base.yaml"},
+			expected: "port: 80\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := []string{"merge"}
+			for _, arg := range test.args {
+				if strings.Contains(arg, ".") {
+					arg = filepath.Join(dir, arg)
+				}
+				args = append(args, arg)
+			}
+
+			stdout, stderr, code := runMain(t, args...)
+			if code != 0 {
+				t.Fatalf("expected exit code 0, got: %d, stderr: %s", code, stderr)
+			}
+			if stdout != test.expected {
+				t.Fatalf("Merged config doesn't equal expected. printed:\n%s\nexpected:\n%s", stdout, test.expected)
+			}
+		})
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"ok.yml":  "port: 80\n",
+		"dup.yml": "port: 80\nport: 81\n",
+	})
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{name: "no command", args: nil, code: 2, stderr: "Usage:"},
+		{name: "unknown command", args: []string{"unknown"}, code: 2, stderr: `unknown command "unknown"`},
+		{name: "help", args: []string{"help"}, code: 0, stdout: "Usage:"},
+		{name: "missing arguments", args: []string{"lint"}, code: 2, stderr: "Usage: configuro lint"},
+		{name: "lint ok", args: []string{"lint", "ok.yml"}, code: 0, stdout: "ok.yml: ok"},
+		{name: "lint problems", args: []string{"lint", "ok.yml", "dup.yml"}, code: 1, stdout: `duplicate key "port"`},
+		{name: "error", args: []string{"convert", "missing.yml"}, code: 1, stderr: "error:"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var args []string
+			for _, arg := range test.args {
+				if strings.HasSuffix(arg, ".yml") {
+					arg = filepath.Join(dir, arg)
+				}
+				args = append(args, arg)
+			}
+
+			stdout, stderr, code := runMain(t, args...)
+			if code != test.code {
+				t.Fatalf("expected exit code %d, got: %d, stdout: %s, stderr: %s", test.code, code, stdout, stderr)
+			}
+			if !strings.Contains(stdout, test.stdout) || !strings.Contains(stderr, test.stderr) {
+				t.Fatalf("expected stdout to contain %q and stderr to contain %q, stdout: %s, stderr: %s", test.stdout, test.stderr, stdout, stderr)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package configuro
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,7 +27,25 @@ type Config struct {
 	configFilepath             string
 	configFilepathEnv          bool
 	configFilepathEnvName      string
+	configSearch               bool
+	configSearchAppName        string
+	configSearchPaths          []string
+	configSearchMergeAll       bool
+	configFilesUsed            []string
 	configEnvExpand            bool
+	configExpandKeys           bool
+	configJSONComments         bool
+	configTemplate             bool
+	preserveMapKeysCase        bool
+	mapKeysCase                map[string]string
+	keyAliases                 map[string]string
+	migrations                 map[int]func(doc map[string]interface{}) error
+	migrationsWriteBack        bool
+	saveComments               bool
+	warningLogger              func(warning string)
+	sources                    map[string]Source
+	parentSources              map[string]Source
+	schema                     interface{}
 	validateFuncStopOnFirstErr bool
 	validateRecursive          bool
 	validateUsingTags          bool
@@ -81,14 +100,12 @@ func DefaultOptions() []ConfigOptions {
 		WithValidateByFunc(false, true),
 		Tag("config", "validate"),
 		KeyDelimiter("."),
+		WithWarningLogger(logWarning),
 	}
 }
 
 func (c *Config) initialize() error {
 
-	// Init Viper
-	c.viper = viper.NewWithOptions(viper.KeyDelimiter(c.keyDelimiter))
-
 	if c.envDotFileLoad {
 		// load .env vars
 		if _, err := os.Stat(c.envDotFilePath); err == nil || !os.IsNotExist(err) {
@@ -99,9 +116,8 @@ func (c *Config) initialize() error {
 		}
 	}
 
-	if c.envLoad {
-		c.enableEnvLoad()
-	}
+	// Init Viper
+	c.initViper()
 
 	if c.configFileLoad {
 		err := c.enableConfigFileLoad()
@@ -174,13 +190,15 @@ func WithoutLoadDotEnv() ConfigOptions {
 }
 
 //WithLoadFromConfigFile Load Config from file provided by filepath.
-// - Supported Formats/Extensions (.yml, .yaml, .toml, .json)
+// - Supported Formats/Extensions (.yml, .yaml, .toml, .json, .jsonc, .json5, .hcl, .ini, .properties, .env)
+// - Keys in .env files follow the same naming as Environment Variables (e.g `CONFIG_NESTED__KEY`)
 // - ErrIfFileNotFound let you determine behavior when files is not found.
 //   Typically if you rely on Environment Variables you may not need to Error if file is not found.
 func WithLoadFromConfigFile(Filepath string, ErrIfFileNotFound bool) ConfigOptions {
 	return func(h *Config) error {
 		h.configFileLoad = true
 		h.configFileErrIfNotFound = ErrIfFileNotFound
+		h.configSearch = false
 		return h.setConfigFilepath(Filepath)
 	}
 }
@@ -191,6 +209,46 @@ func WithoutLoadFromConfigFile() ConfigOptions {
 		h.configFileLoad = false
 		h.configFileErrIfNotFound = false
 		h.configFilepath = ""
+		h.configSearch = false
+		return nil
+	}
+}
+
+//WithConfigSearchPaths Load Config from a file named `config` found by searching the provided directories in order.
+// - Every extension in the supported formats is tried in each directory (e.g `config.yml`, `config.json`, ...)
+// - If no paths are provided, the conventional lookup is used: `./`, `$XDG_CONFIG_HOME/<appName>/`, `~/.config/<appName>/`, `/etc/<appName>/`.
+// - Paths can contain Environment Variables and a leading `~`, and `<app>` which is replaced by appName.
+// - Only the first file found is loaded, unless WithConfigSearchMergeAll() is set.
+// - Files actually loaded are reported by ConfigFilesUsed().
+func WithConfigSearchPaths(appName string, paths ...string) ConfigOptions {
+	return func(h *Config) error {
+		if appName == "" && len(paths) == 0 {
+			return fmt.Errorf("app name must be declared to use default search paths")
+		}
+		h.configFileLoad = true
+		h.configSearch = true
+		h.configSearchAppName = appName
+		h.configSearchPaths = paths
+		return nil
+	}
+}
+
+//WithoutConfigSearchPaths Disable searching for config file in search paths.
+func WithoutConfigSearchPaths() ConfigOptions {
+	return func(h *Config) error {
+		h.configSearch = false
+		h.configSearchAppName = ""
+		h.configSearchPaths = nil
+		h.configSearchMergeAll = false
+		return nil
+	}
+}
+
+//WithConfigSearchMergeAll Merge all config files found in search paths instead of loading the first match only.
+// Files found in earlier paths take precedence over files found in later ones.
+func WithConfigSearchMergeAll() ConfigOptions {
+	return func(h *Config) error {
+		h.configSearchMergeAll = true
 		return nil
 	}
 }
@@ -213,9 +271,46 @@ func WithoutEnvConfigPathOverload() ConfigOptions {
 	}
 }
 
+//WithJSONComments Allow comments and trailing commas in .json config files (same as .jsonc files).
+func WithJSONComments() ConfigOptions {
+	return func(h *Config) error {
+		h.configJSONComments = true
+		return nil
+	}
+}
+
+//WithoutJSONComments Disallow comments and trailing commas in .json config files.
+func WithoutJSONComments() ConfigOptions {
+	return func(h *Config) error {
+		h.configJSONComments = false
+		return nil
+	}
+}
+
+//WithTemplateRendering Render config files through text/template before parsing them.
+// Available functions are: env, default, required, file, b64dec, toJSON, and hostname.
+// Example: {{ if eq (env "REGION") "eu" }}endpoint: eu.example.com{{ end }}
+func WithTemplateRendering() ConfigOptions {
+	return func(h *Config) error {
+		h.configTemplate = true
+		return nil
+	}
+}
+
+//WithoutTemplateRendering Disable rendering config files through text/template.
+func WithoutTemplateRendering() ConfigOptions {
+	return func(h *Config) error {
+		h.configTemplate = false
+		return nil
+	}
+}
+
 //WithExpandEnvVars Expand config values with ${ENVVAR} with the value of ENVVAR in environment variables.
 // Example: ${DB_URI}:3201  ==> localhost:3201 (Where $DB_URI was equal "localhost" )
 // You can set default if ENVVAR is not set using the following format ${ENVVAR|defaultValue}
+// POSIX-style ${ENVVAR:-default}, ${ENVVAR-default}, ${ENVVAR:?error}, ${ENVVAR:+alternate}, nested defaults, and $$ escaping are supported too.
+// Expansion is done on the merged config of all sources before decoding, including nested values of any type,
+// fields tagged with `expand:"false"` are not expanded.
 func WithExpandEnvVars() ConfigOptions {
 	return func(h *Config) error {
 		h.configEnvExpand = true
@@ -231,6 +326,40 @@ func WithoutExpandEnvVars() ConfigOptions {
 	}
 }
 
+//WithExpandKeys Expand ${ENVVAR} expressions in config files' keys too (e.g `${REGION}_endpoint: ...`)
+func WithExpandKeys() ConfigOptions {
+	return func(h *Config) error {
+		h.configExpandKeys = true
+		return nil
+	}
+}
+
+//WithoutExpandKeys Disable Expanding Environment Variables in config files' keys.
+func WithoutExpandKeys() ConfigOptions {
+	return func(h *Config) error {
+		h.configExpandKeys = false
+		return nil
+	}
+}
+
+//WithPreserveMapKeysCase Preserve the original case of keys of map fields (e.g `word_map: {FooBar: 1}` is loaded as `FooBar` instead of `foobar`).
+// Struct fields are still matched case insensitively. If a key is set by multiple config files, the case of the key in the file
+// with the highest precedence is used, keys set only by Environment Variables use the case of the Environment Variable name.
+func WithPreserveMapKeysCase() ConfigOptions {
+	return func(h *Config) error {
+		h.preserveMapKeysCase = true
+		return nil
+	}
+}
+
+//WithoutPreserveMapKeysCase Load keys of map fields in lower case.
+func WithoutPreserveMapKeysCase() ConfigOptions {
+	return func(h *Config) error {
+		h.preserveMapKeysCase = false
+		return nil
+	}
+}
+
 //WithValidateByTags Validate using struct tags.
 func WithValidateByTags() ConfigOptions {
 	return func(h *Config) error {
@@ -269,6 +398,27 @@ func WithoutValidateByFunc() ConfigOptions {
 	}
 }
 
+//WithSchema Validate the merged config document against a JSON Schema before decoding it into the config struct.
+// Use it when the schema is the shared contract of the config instead of the struct (e.g configs consumed by other languages).
+// Errors are reported with the key path and the config source of the invalid value.
+func WithSchema(schema []byte) ConfigOptions {
+	return func(h *Config) error {
+		err := json.Unmarshal(schema, &h.schema)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON schema: %v", err)
+		}
+		return nil
+	}
+}
+
+//WithoutSchema Disable validating config documents against a JSON Schema.
+func WithoutSchema() ConfigOptions {
+	return func(h *Config) error {
+		h.schema = nil
+		return nil
+	}
+}
+
 //Tag Change default tag.
 func Tag(structTag, validateTag string) ConfigOptions {
 	return func(h *Config) error {
@@ -278,6 +428,91 @@ func Tag(structTag, validateTag string) ConfigOptions {
 	}
 }
 
+//WithKeyAliases Map deprecated keys to the keys replacing them (e.g {"db.host": "database.host"}).
+// Values set under a deprecated key (by config files or Environment Variables) are loaded into the new key and a warning is logged.
+// Fields can declare the deprecated keys they replace using the `deprecated:"old.key"` tag too.
+// Loading fails if both a deprecated key and its new key are set with different values.
+func WithKeyAliases(aliases map[string]string) ConfigOptions {
+	return func(h *Config) error {
+		if h.keyAliases == nil {
+			h.keyAliases = make(map[string]string, len(aliases))
+		}
+		for deprecatedKey, newKey := range aliases {
+			h.keyAliases[deprecatedKey] = newKey
+		}
+		return nil
+	}
+}
+
+//WithMigrations Upgrade config files written for older versions of the config struct before loading them.
+// The version of a config file is its top level `version` key (files without it are at version 0), and the migration
+// at key N upgrade a config document from version N to N+1 by modifying it in place. Config files are migrated up to
+// the current version (one more than the latest migration) and loading fails for files newer than the current version.
+func WithMigrations(migrations map[int]func(doc map[string]interface{}) error) ConfigOptions {
+	return func(h *Config) error {
+		h.migrations = migrations
+		return nil
+	}
+}
+
+//WithoutMigrations Disable migrating config files.
+func WithoutMigrations() ConfigOptions {
+	return func(h *Config) error {
+		h.migrations = nil
+		return nil
+	}
+}
+
+//WithMigrationsWriteBack Write migrated config files back to disk, so they're migrated once.
+// Only YAML, JSON, and TOML files can be written back. Files including other files and JSONC/JSON5 files are not written back,
+// a warning is logged instead. Comments are kept only using WithSaveComments.
+func WithMigrationsWriteBack() ConfigOptions {
+	return func(h *Config) error {
+		h.migrationsWriteBack = true
+		return nil
+	}
+}
+
+//WithoutMigrationsWriteBack Migrate config files in memory only.
+func WithoutMigrationsWriteBack() ConfigOptions {
+	return func(h *Config) error {
+		h.migrationsWriteBack = false
+		return nil
+	}
+}
+
+//WithSaveComments Keep the comments and keys order of an existing YAML file when writing to it (using Save or migrations write back).
+func WithSaveComments() ConfigOptions {
+	return func(h *Config) error {
+		h.saveComments = true
+		return nil
+	}
+}
+
+//WithoutSaveComments Overwrite existing files without keeping their comments.
+func WithoutSaveComments() ConfigOptions {
+	return func(h *Config) error {
+		h.saveComments = false
+		return nil
+	}
+}
+
+//WithWarningLogger Set the function warnings (e.g using deprecated keys) are logged with. Default logs using the standard log package.
+func WithWarningLogger(logger func(warning string)) ConfigOptions {
+	return func(h *Config) error {
+		h.warningLogger = logger
+		return nil
+	}
+}
+
+//WithoutWarningLogger Disable logging warnings.
+func WithoutWarningLogger() ConfigOptions {
+	return func(h *Config) error {
+		h.warningLogger = nil
+		return nil
+	}
+}
+
 //KeyDelimiter Сhange default key delimiter.
 func KeyDelimiter(keyDelimiter string) ConfigOptions {
 	return func(h *Config) error {
@@ -286,7 +521,7 @@ func KeyDelimiter(keyDelimiter string) ConfigOptions {
 	}
 }
 
-var supportedExt = []string{".json", ".toml", ".yaml", ".yml"}
+var supportedExt = []string{".json", ".jsonc", ".json5", ".toml", ".yaml", ".yml", ".hcl", ".ini", ".properties", ".env"}
 
 func isSupportedExtension(ext string) bool {
 	found := false
@@ -333,15 +568,13 @@ func (c *Config) enableValidateUsingTag() {
 
 func (c *Config) addDecoderConfig() {
 	DefaultDecodeHookFuncs := []mapstructure.DecodeHookFunc{
+		c.valueDecodeHook(),
 		stringJSONArrayToSlice(),
 		stringJSONObjToMap(),
 		stringJSONObjToStruct(),
 		mapstructure.StringToTimeDurationHookFunc(),
 		mapstructure.StringToIPHookFunc(),
 	}
-	if c.configEnvExpand {
-		DefaultDecodeHookFuncs = append([]mapstructure.DecodeHookFunc{expandEnvVariablesWithDefaults()}, DefaultDecodeHookFuncs...)
-	}
 	c.decodeHook = viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
 		DefaultDecodeHookFuncs...,
 	))
@@ -356,13 +589,22 @@ func (c *Config) enableConfigFileLoad() error {
 			if err != nil {
 				return err
 			}
+			// An explicit config file path takes precedence over searching.
+			c.configSearch = false
 		}
 	}
 
-	c.viper.SetConfigFile(c.configFilepath)
 	return nil
 }
 
+func (c *Config) initViper() {
+	c.viper = viper.NewWithOptions(viper.KeyDelimiter(c.keyDelimiter))
+
+	if c.envLoad {
+		c.enableEnvLoad()
+	}
+}
+
 func (c *Config) enableEnvLoad() {
 	c.viper.SetEnvPrefix(c.envPrefix)
 	// Viper add the `prefix` + '_' to the Key *before* passing it to Key Replacer,causing the replacer to replace the '_' with '__' when it shouldn't.
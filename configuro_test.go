@@ -2,6 +2,8 @@
 package configuro_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -9,6 +11,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sherifabdlnaby/configuro"
 	"go.uber.org/multierr"
@@ -176,48 +179,1899 @@ func TestLoadFromEnvVarsOnly(t *testing.T) {
 	}
 }
 
+type sampleHost struct {
+	Addr string `config:"addr" validate:"required"`
+	Port int    `config:"port" default:"5432"`
+}
+
+type sampleDatabase struct {
+	Hosts    []sampleHost  `config:"hosts" description:"Database hosts, the first is the primary."`
+	Password string        `config:"password" secret:"true"`
+	Timeout  time.Duration `config:"timeout" default:"5s"`
+}
+
+type sampleConfig struct {
+	Name     string                `config:"name" description:"Application name." validate:"required"`
+	Debug    configuro.Value[bool] `config:"debug"`
+	Tags     []string              `config:"tags" default:"a,b"`
+	Database sampleDatabase        `config:"database"`
+	Workers  int                   `config:"workers" validate:"min=1"`
+}
+
+func TestGenerateSample(t *testing.T) {
+	sample, err := configuro.GenerateSample(&sampleConfig{Workers: 4}, "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# Application name.
+# validate: required
+name: ""
+debug: false
+tags: [a, b]
+database:
+  # Database hosts, the first is the primary.
+  hosts:
+    - # validate: required
+      addr: ""
+      port: 5432
+  # secret, set it using an Environment Variable.
+  password: <secret>
+  timeout: 5s
+# validate: min=1
+workers: 4
+`
+	if string(sample) != expected {
+		t.Fatalf("Generated sample doesn't equal expected sample. generated:\n%s\nexpected:\n%s", sample, expected)
+	}
+
+	// Samples of every format can be loaded.
+	for _, format := range []string{"yml", "json", "jsonc", "toml"} {
+		t.Run(format, func(t *testing.T) {
+			sample, err := configuro.GenerateSample(&sampleConfig{Workers: 4}, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			configFile, err := ioutil.TempFile("", "TestGenerateSample*."+format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				configFile.Close()
+				os.RemoveAll(configFile.Name())
+			}()
+			_, _ = configFile.Write(sample)
+
+			configLoader, err := configuro.NewConfig(
+				configuro.WithoutLoadFromEnvVars(),
+				configuro.WithoutLoadDotEnv(),
+				configuro.WithLoadFromConfigFile(configFile.Name(), true),
+				configuro.WithoutEnvConfigPathOverload(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := &sampleConfig{}
+			err = configLoader.Load(loaded)
+			if err != nil {
+				t.Fatalf("error loading sample %s: %v", sample, err)
+			}
+
+			if loaded.Workers != 4 || loaded.Database.Timeout != 5*time.Second || loaded.Database.Password != "<secret>" ||
+				len(loaded.Database.Hosts) != 1 || loaded.Database.Hosts[0].Port != 5432 || !reflect.DeepEqual(loaded.Tags, []string{"a", "b"}) {
+				t.Fatalf("Loaded sample doesn't equal expected values. loaded: %v", loaded)
+			}
+		})
+	}
+}
+
+func TestGenerateSampleInvalid(t *testing.T) {
+	type inner struct {
+		X int `config:"x"`
+	}
+	type Outer struct {
+		inner
+		Y int `config:"y"`
+	}
+
+	sample, err := configuro.GenerateSample(&Outer{Y: 1}, "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "inner:\n  x: 0\ny: 1\n"; string(sample) != expected {
+		t.Fatalf("Generated sample doesn't equal expected sample. generated:\n%s\nexpected:\n%s", sample, expected)
+	}
+
+	_, err = configuro.GenerateSample(nil, "yaml")
+	if err == nil || !strings.Contains(err.Error(), "is not a struct") {
+		t.Fatalf("expected a not a struct error, got: %v", err)
+	}
+}
+
+func TestGenerateSampleRecursiveStruct(t *testing.T) {
+	type Node struct {
+		Name     string `config:"name"`
+		Next     *Node  `config:"next"`
+		Children []Node `config:"children"`
+	}
+
+	sample, err := configuro.GenerateSample(Node{}, "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "name: \"\"\nnext: null\nchildren:\n  - {}\n"; string(sample) != expected {
+		t.Fatalf("Generated sample doesn't equal expected sample. generated:\n%s\nexpected:\n%s", sample, expected)
+	}
+}
+
+func TestEnvVarsDoc(t *testing.T) {
+	type Obj struct {
+		sampleConfig `config:",squash"`
+		AnotherWord  string `config:"another_word" env:"ANOTHER_WORD"`
+	}
+
+	envVars := configuro.EnvVars(&Obj{})
+
+	expected := []configuro.EnvVar{
+		{Name: "CONFIG_NAME", Key: "name", Type: "string", Required: true, Description: "Application name."},
+		{Name: "CONFIG_DEBUG", Key: "debug", Type: "bool"},
+		{Name: "CONFIG_TAGS", Key: "tags", Type: "[]string", Default: "a,b"},
+		{Name: "CONFIG_DATABASE_HOSTS_<N>_ADDR", Key: "database.hosts.<N>.addr", Type: "string", Required: true},
+		{Name: "CONFIG_DATABASE_HOSTS_<N>_PORT", Key: "database.hosts.<N>.port", Type: "int", Default: "5432"},
+		{Name: "CONFIG_DATABASE_PASSWORD", Key: "database.password", Type: "string", Secret: true},
+		{Name: "CONFIG_DATABASE_TIMEOUT", Key: "database.timeout", Type: "time.Duration", Default: "5s"},
+		{Name: "CONFIG_WORKERS", Key: "workers", Type: "int"},
+		{Name: "CONFIG_ANOTHER__WORD", Aliases: []string{"ANOTHER_WORD"}, Key: "another_word", Type: "string"},
+	}
+	if !reflect.DeepEqual(envVars, expected) {
+		t.Fatalf("Env vars doesn't equal expected env vars. got: %v, expected: %v", envVars, expected)
+	}
+
+	markdown, err := configuro.EnvVarsDoc(&Obj{}, "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(markdown), "| `CONFIG_NAME` | `string` |  | yes | no | Application name. |\n") ||
+		!strings.Contains(string(markdown), "| `CONFIG_ANOTHER__WORD`, `ANOTHER_WORD` | `string` |") {
+		t.Fatalf("unexpected markdown doc:\n%s", markdown)
+	}
+
+	for _, format := range []string{"text", "json"} {
+		doc, err := configuro.EnvVarsDoc(&Obj{}, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(doc), "CONFIG_DATABASE_HOSTS_<N>_PORT") {
+			t.Fatalf("unexpected %s doc:\n%s", format, doc)
+		}
+	}
+
+	// Names follow the configured prefix.
+	configLoader, err := configuro.NewConfig(configuro.WithLoadFromEnvVars("APP"), configuro.WithoutLoadDotEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := configLoader.EnvVars(&Obj{})[0].Name; name != "APP_NAME" {
+		t.Fatalf("expected APP_NAME, got: %s", name)
+	}
+}
+
+func TestEnvVarsDocRecursiveStruct(t *testing.T) {
+	type Node struct {
+		Name string `config:"name"`
+		Next *Node  `config:"next"`
+	}
+
+	envVars := configuro.EnvVars(Node{})
+
+	expected := []configuro.EnvVar{{Name: "CONFIG_NAME", Key: "name", Type: "string"}}
+	if !reflect.DeepEqual(envVars, expected) {
+		t.Fatalf("Env vars doesn't equal expected env vars. got: %v, expected: %v", envVars, expected)
+	}
+
+	doc, err := configuro.EnvVarsDoc(Node{}, "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(doc), "CONFIG_NAME") {
+		t.Fatalf("unexpected text doc:\n%s", doc)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	type Obj struct {
+		sampleConfig `config:",squash"`
+		Level        string            `config:"level" validate:"oneof=debug info warn"`
+		Endpoint     string            `config:"endpoint" validate:"required,url"`
+		Admins       []string          `config:"admins" validate:"min=1,dive,email"`
+		Listen       string            `config:"listen" validate:"ip"`
+		Labels       map[string]string `config:"labels"`
+		Ratio        float64           `config:"ratio" validate:"gte=0,lte=1"`
+	}
+
+	schema, err := configuro.JSONSchema(&Obj{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	err = json.Unmarshal(schema, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected map[string]interface{}
+	err = json.Unmarshal([]byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["name", "endpoint"],
+  "properties": {
+    "name": {"type": "string", "description": "Application name."},
+    "debug": {"type": "boolean"},
+    "tags": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"]},
+    "database": {
+      "type": "object",
+      "properties": {
+        "hosts": {
+          "type": "array",
+          "description": "Database hosts, the first is the primary.",
+          "items": {
+            "type": "object",
+            "required": ["addr"],
+            "properties": {
+              "addr": {"type": "string"},
+              "port": {"type": "integer", "default": 5432}
+            }
+          }
+        },
+        "password": {"type": "string", "writeOnly": true},
+        "timeout": {"type": ["string", "integer"], "default": "5s"}
+      }
+    },
+    "workers": {"type": "integer", "minimum": 1},
+    "level": {"type": "string", "enum": ["debug", "info", "warn"]},
+    "endpoint": {"type": "string", "format": "uri"},
+    "admins": {"type": "array", "minItems": 1, "items": {"type": "string", "format": "email"}},
+    "listen": {"type": "string", "anyOf": [{"format": "ipv4"}, {"format": "ipv6"}]},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "ratio": {"type": "number", "minimum": 0, "maximum": 1}
+  }
+}`), &expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Generated schema doesn't equal expected schema. generated:\n%s", schema)
+	}
+}
+
+func TestSchemaValidation(t *testing.T) {
+	type Obj struct {
+		Name   string
+		Port   int
+		Level  string
+		Listen string
+		Hosts  []string
+	}
+
+	schema := []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["name", "port"],
+  "properties": {
+    "name": {"type": "string"},
+    "port": {"type": "integer", "minimum": 1, "maximum": 65535},
+    "level": {"enum": ["debug", "info"]},
+    "listen": {"anyOf": [{"format": "ipv4"}, {"format": "ipv6"}]},
+    "hosts": {"type": "array", "items": {"$ref": "#/$defs/host"}}
+  },
+  "additionalProperties": false,
+  "$defs": {
+    "host": {"type": "string", "format": "hostname"}
+  }
+}`)
+
+	tests := []struct {
+		name   string
+		file   string
+		env    map[string]string
+		errors []string
+	}{
+		{
+			name: "valid",
+			file: "name: app\nport: 8080\nlevel: info\nlisten: 127.0.0.1\nhosts: [a.example.com]\n",
+		},
+		{
+			name: "env string coerced to integer",
+			file: "name: app\nport: 8080\n",
+			env:  map[string]string{"SCHEMA_PORT": "9090"},
+		},
+		{
+			name: "env out of range",
+			file: "name: app\nport: 8080\n",
+			env:  map[string]string{"SCHEMA_PORT": "70000"},
+			errors: []string{
+				"port (env SCHEMA_PORT): must be <= 65535",
+			},
+		},
+		{
+			name: "file errors",
+			file: "port: 8080\nlevel: trace\nlisten: localhost\nhosts: [a.example.com, -bad-]\nextra: true\n",
+			errors: []string{
+				"name: is required",
+				"extra (file %s): is not allowed",
+				"hosts.1 (file %s): must be a valid hostname",
+				"level (file %s): must be one of: debug, info",
+				"listen (file %s): must be a valid ipv4 or must be a valid ipv6",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configFileYaml, err := ioutil.TempFile("", "TestSchemaValidation*.yml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				configFileYaml.Close()
+				os.RemoveAll(configFileYaml.Name())
+			}()
+			_, _ = configFileYaml.Write([]byte(test.file))
+
+			for name, value := range test.env {
+				_ = os.Setenv(name, value)
+				defer os.Unsetenv(name)
+			}
+
+			configLoader, err := configuro.NewConfig(
+				configuro.WithLoadFromEnvVars("SCHEMA"),
+				configuro.WithoutLoadDotEnv(),
+				configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+				configuro.WithoutEnvConfigPathOverload(),
+				configuro.WithSchema(schema),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = configLoader.Load(&Obj{})
+			if len(test.errors) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			errs := []error{err}
+			var validationErrs configuro.ErrValidationErrors
+			if errors.As(err, &validationErrs) {
+				errs = validationErrs.Errors()
+			}
+			if len(errs) != len(test.errors) {
+				t.Fatalf("expected %d errors, got: %v", len(test.errors), err)
+			}
+			for i, expected := range test.errors {
+				if strings.Contains(expected, "%s") {
+					expected = fmt.Sprintf(expected, configFileYaml.Name())
+				}
+				var schemaErr *configuro.ErrValidationSchema
+				if !errors.As(errs[i], &schemaErr) || schemaErr.Error() != expected {
+					t.Fatalf("expected error \"%s\", got: %v", expected, errs[i])
+				}
+			}
+		})
+	}
+
+	_, err := configuro.NewConfig(configuro.WithSchema([]byte("{")))
+	if err == nil {
+		t.Fatal("expected error parsing invalid schema")
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	configFileJSON, err := ioutil.TempFile("", "TestDocuments*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileJSON.Close()
+		os.RemoveAll(configFileJSON.Name())
+	}()
+	_, _ = configFileJSON.Write([]byte(`{"Port": 8080, "database": {"Host": "db", "hosts": ["a", "b"]}}`))
+
+	configLoader, err := configuro.NewConfig(configuro.WithLoadFromEnvVars("APP"), configuro.WithoutLoadDotEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := configLoader.ReadFile(configFileJSON.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := configuro.MergeDocuments(doc, map[string]interface{}{
+		"port":     9090,
+		"database": map[string]interface{}{"HOST": "db2"},
+	})
+	expected := map[string]interface{}{
+		"port":     9090,
+		"database": map[string]interface{}{"HOST": "db2", "hosts": []interface{}{"a", "b"}},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("Merged document doesn't equal expected document. merged: %#v", merged)
+	}
+	if doc["database"].(map[string]interface{})["Host"] != "db" {
+		t.Fatalf("Merging modified the merged documents: %#v", doc)
+	}
+
+	toml, err := configuro.MarshalDocument(doc, "toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(toml), "Port = 8080\n") {
+		t.Fatalf("expected integer port in toml:\n%s", toml)
+	}
+
+	if name := configLoader.EnvVarName("database.max_conns"); name != "APP_DATABASE_MAX__CONNS" {
+		t.Fatalf("expected APP_DATABASE_MAX__CONNS, got: %s", name)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	configFile, err := ioutil.TempFile("", "TestDiff*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFile.Close()
+		os.RemoveAll(configFile.Name())
+	}()
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("DIFF"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFile.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	load := func(yaml string) *sampleConfig {
+		err := ioutil.WriteFile(configFile.Name(), []byte(yaml), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded := &sampleConfig{}
+		err = configLoader.Load(loaded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loaded
+	}
+
+	old := load(`
+name: app
+tags: [a]
+database:
+  hosts: [{addr: a, port: 5432}]
+  password: old
+workers: 1
+`)
+	new := load(`
+name: app
+debug: true
+tags: [a, b]
+database:
+  hosts: [{addr: a, port: 5432}, {addr: b, port: 5433}]
+  password: new
+workers: 2
+`)
+
+	expected := []configuro.Change{
+		{Key: "debug", Type: configuro.ChangeAdded, New: true},
+		{Key: "tags", Type: configuro.ChangeModified, Old: []string{"a"}, New: []string{"a", "b"}},
+		{Key: "database.hosts.1.addr", Type: configuro.ChangeAdded, New: "b"},
+		{Key: "database.hosts.1.port", Type: configuro.ChangeAdded, New: 5433},
+		{Key: "database.password", Type: configuro.ChangeModified, Old: "<secret>", New: "<secret>"},
+		{Key: "workers", Type: configuro.ChangeModified, Old: 1, New: 2},
+	}
+	changes := configLoader.Diff(old, new)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Changes don't equal expected changes. changes: %v", changes)
+	}
+
+	if changes := configuro.Diff(new, new); len(changes) != 0 {
+		t.Fatalf("expected no changes, got: %v", changes)
+	}
+	if changes := configuro.Diff(new, old); changes[0].Type != configuro.ChangeRemoved || changes[0].Old != true {
+		t.Fatalf("expected debug to be removed, got: %v", changes)
+	}
+}
+
+func TestStore(t *testing.T) {
+	configFile, err := ioutil.TempFile("", "TestStore*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFile.Close()
+		os.RemoveAll(configFile.Name())
+	}()
+	_, _ = configFile.Write([]byte(`
+name: app
+tags: [a]
+database:
+  hosts: [{addr: a, port: 5432}]
+workers: 1
+`))
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("STORE"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFile.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := configuro.NewStore[sampleConfig](configLoader)
+	if store.Get() != nil {
+		t.Fatal("expected no config before loading")
+	}
+
+	notified := make(map[string]int)
+	for _, key := range []string{"", "database", "database.hosts.0.addr", "workers"} {
+		key := key
+		store.Subscribe(key, func(old, new *sampleConfig) {
+			notified[key]++
+		})
+	}
+	unsubscribe := store.Subscribe("tags", func(old, new *sampleConfig) {
+		t.Fatal("expected unsubscribed fn not to be called")
+	})
+	unsubscribe()
+
+	err = store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := store.Get()
+	if loaded.Name != "app" || loaded.Workers != 1 {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v", loaded)
+	}
+
+	err = store.Update(func(config *sampleConfig) {
+		config.Workers = 2
+		config.Tags[0] = "b"
+		config.Database.Hosts[0].Addr = "b"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := store.Get()
+	if updated.Workers != 2 || updated.Database.Hosts[0].Addr != "b" {
+		t.Fatalf("Updated Values doesn't equal expected values. updated: %v", updated)
+	}
+	if loaded.Workers != 1 || loaded.Tags[0] != "a" || loaded.Database.Hosts[0].Addr != "a" {
+		t.Fatalf("Update modified the previous config: %v", loaded)
+	}
+
+	err = store.Update(func(config *sampleConfig) {
+		config.Workers = 0
+	})
+	if err == nil {
+		t.Fatal("expected a validation error")
+	}
+	if store.Get() != updated {
+		t.Fatal("expected the config to be kept if validation fails")
+	}
+
+	expected := map[string]int{"": 2, "database": 2, "database.hosts.0.addr": 2, "workers": 2}
+	if !reflect.DeepEqual(notified, expected) {
+		t.Fatalf("Notifications don't equal expected notifications. notified: %v", notified)
+	}
+
+	err = store.Update(func(config *sampleConfig) {
+		config.Workers = 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notified["workers"] != 3 || notified["database"] != 2 {
+		t.Fatalf("expected only workers subscribers to be notified. notified: %v", notified)
+	}
+}
+
+func TestDefaultTag(t *testing.T) {
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("DEFAULTS"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithoutLoadFromConfigFile(),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = os.Setenv("DEFAULTS_DATABASE_TIMEOUT", "10s")
+	defer os.Unsetenv("DEFAULTS_DATABASE_TIMEOUT")
+
+	loaded := &sampleConfig{}
+	err = configLoader.Load(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Database.Timeout != 10*time.Second || !reflect.DeepEqual(loaded.Tags, []string{"a", "b"}) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v", loaded)
+	}
+	if source := configLoader.Source("tags"); source.Kind != configuro.SourceDefault {
+		t.Fatalf("expected tags to be loaded from default, source: %v", source)
+	}
+}
+
+func TestNestedDefaultTag(t *testing.T) {
+	type Obj struct {
+		Database sampleDatabase        `config:"database"`
+		Replicas map[string]sampleHost `config:"replicas"`
+		Primary  *sampleHost           `config:"primary"`
+		Backup   *sampleHost           `config:"backup"`
+	}
+
+	configFileYaml, err := ioutil.TempFile("", "TestNestedDefaultTag*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	_, _ = configFileYaml.Write([]byte(`
+database:
+  hosts:
+    - addr: a
+    - addr: b
+      port: 6432
+replicas:
+  east:
+    addr: c
+primary:
+  addr: d
+`))
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &Obj{}
+	err = configLoader.Load(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Obj{
+		Database: sampleDatabase{
+			Hosts:   []sampleHost{{Addr: "a", Port: 5432}, {Addr: "b", Port: 6432}},
+			Timeout: 5 * time.Second,
+		},
+		Replicas: map[string]sampleHost{"east": {Addr: "c", Port: 5432}},
+		Primary:  &sampleHost{Addr: "d", Port: 5432},
+	}
+	if !reflect.DeepEqual(loaded, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %+v, expected: %+v", loaded, expected)
+	}
+	if source := configLoader.Source("database.hosts.0.port"); source.Kind != configuro.SourceDefault {
+		t.Fatalf("expected database.hosts.0.port to be loaded from default, source: %v", source)
+	}
+	if source := configLoader.Source("primary"); source.Kind != configuro.SourceFile {
+		t.Fatalf("expected primary to be loaded from file, source: %v", source)
+	}
+	if configLoader.IsSet("backup") {
+		t.Fatal("expected backup not to be set")
+	}
+
+	// Defaults are set in elements of JSON encoded Environment Variables, and in pointers set by Environment Variables.
+	configLoader, err = configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("NESTED_DEFAULTS"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithoutLoadFromConfigFile(),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = os.Setenv("NESTED_DEFAULTS_DATABASE_HOSTS", `[{"addr": "e"}]`)
+	_ = os.Setenv("NESTED_DEFAULTS_BACKUP_ADDR", "f")
+	defer os.Unsetenv("NESTED_DEFAULTS_DATABASE_HOSTS")
+	defer os.Unsetenv("NESTED_DEFAULTS_BACKUP_ADDR")
+
+	loaded = &Obj{}
+	err = configLoader.Load(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = &Obj{
+		Database: sampleDatabase{Hosts: []sampleHost{{Addr: "e", Port: 5432}}, Timeout: 5 * time.Second},
+		Backup:   &sampleHost{Addr: "f", Port: 5432},
+	}
+	if !reflect.DeepEqual(loaded, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %+v, expected: %+v", loaded, expected)
+	}
+}
+
+func TestSave(t *testing.T) {
+	type Database struct {
+		Host    string `config:"host"`
+		Port    int    `config:"port"`
+		Timeout time.Duration
+	}
+
+	type Obj struct {
+		Name     string            `config:"name"`
+		Database Database          `config:"database"`
+		Tags     []string          `config:"tags"`
+		Labels   map[string]string `config:"labels"`
+		Debug    configuro.Value[bool]
+		Workers  configuro.Value[int]
+		Internal string `config:"-"`
+	}
+
+	obj := Obj{
+		Name:     "app",
+		Database: Database{Host: "localhost", Port: 5432, Timeout: 5 * time.Second},
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"env": "prod"},
+		Internal: "not saved",
+	}
+
+	for _, ext := range []string{".yml", ".json", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "TestSave")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "config"+ext)
+
+			configLoader, err := configuro.NewConfig(
+				configuro.WithoutLoadFromEnvVars(),
+				configuro.WithoutLoadDotEnv(),
+				configuro.WithLoadFromConfigFile(path, true),
+				configuro.WithoutEnvConfigPathOverload(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = configLoader.Save(&obj, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := &Obj{}
+			err = configLoader.Load(loaded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := obj
+			expected.Internal = ""
+			if !reflect.DeepEqual(*loaded, expected) {
+				t.Fatalf("Loaded Values doesn't equal saved values. loaded: %v, expected: %v", loaded, expected)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		configLoader, err := configuro.NewConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = configLoader.Save(&obj, filepath.Join(os.TempDir(), "config.ini"))
+		if err == nil {
+			t.Fatal("expected an error saving to an unsupported format")
+		}
+	})
+}
+
+func TestSaveKeepYAMLComments(t *testing.T) {
+	type Obj struct {
+		Port    int
+		Host    string
+		Workers int
+	}
+
+	configFileYaml, err := ioutil.TempFile("", "TestSaveKeepYAMLComments*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	_, _ = configFileYaml.Write([]byte(`# Server config
+port: 80 # the listening port
+
+# where to listen
+host: localhost
+`))
+
+	configLoader, err := configuro.NewConfig(configuro.WithSaveComments())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = configLoader.Save(&Obj{Port: 8080, Host: "0.0.0.0", Workers: 4}, configFileYaml.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ioutil.ReadFile(configFileYaml.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# Server config
+port: 8080 # the listening port
+# where to listen
+host: 0.0.0.0
+Workers: 4
+`
+	if string(saved) != expected {
+		t.Fatalf("Saved file doesn't equal expected file. saved:\n%s\nexpected:\n%s", saved, expected)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	type Database struct {
+		Host string
+		Port int
+	}
+
+	type Obj struct {
+		Version  int
+		Database Database
+	}
+
+	configFileYaml, err := ioutil.TempFile("", "TestMigrations*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	_, _ = configFileYaml.Write([]byte(`
+db_host: localhost
+db_port: 5432
+`))
+
+	applied := 0
+	migrations := map[int]func(doc map[string]interface{}) error{
+		// v0 -> v1: move db_host and db_port under database.
+		0: func(doc map[string]interface{}) error {
+			applied++
+			doc["database"] = map[string]interface{}{"host": doc["db_host"], "port": doc["db_port"]}
+			delete(doc, "db_host")
+			delete(doc, "db_port")
+			return nil
+		},
+		// v1 -> v2: default port changed.
+		1: func(doc map[string]interface{}) error {
+			applied++
+			database := doc["database"].(map[string]interface{})
+			if database["port"] == 5432 {
+				database["port"] = 6432
+			}
+			return nil
+		},
+	}
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithMigrations(migrations),
+		configuro.WithMigrationsWriteBack(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Obj{Version: 2, Database: Database{Host: "localhost", Port: 6432}}
+
+	obj := &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*obj, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj, expected)
+	}
+	if applied != 2 {
+		t.Fatalf("expected 2 migrations to be applied, applied: %d", applied)
+	}
+
+	// The migrated file was written back, so migrations are not applied again.
+	obj = &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*obj, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj, expected)
+	}
+	if applied != 2 {
+		t.Fatalf("expected migrations not to be applied to the migrated file, applied: %d", applied)
+	}
+
+	// Files newer than the current version are rejected.
+	_ = ioutil.WriteFile(configFileYaml.Name(), []byte("version: 3\n"), 0644)
+	err = configLoader.Load(&Obj{})
+	if err == nil || !strings.Contains(err.Error(), "newer than the supported version 2") {
+		t.Fatalf("expected a newer version error, got: %v", err)
+	}
+
+	// Missing migrations are reported.
+	delete(migrations, 0)
+	_ = ioutil.WriteFile(configFileYaml.Name(), []byte("db_host: localhost\n"), 0644)
+	err = configLoader.Load(&Obj{})
+	if err == nil || !strings.Contains(err.Error(), "no migration from config version 0") {
+		t.Fatalf("expected a missing migration error, got: %v", err)
+	}
+}
+
+func TestMigrationsWriteBackSkipped(t *testing.T) {
+	type Obj struct {
+		Version int
+		Port    int
+		Host    string
+	}
+
+	dir, err := ioutil.TempDir("", "TestMigrationsWriteBackSkipped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	migrations := map[int]func(doc map[string]interface{}) error{
+		0: func(doc map[string]interface{}) error {
+			doc["port"] = 8080
+			return nil
+		},
+	}
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "host.yml"), []byte("host: localhost\n"), 0644)
+
+	files := map[string]string{
+		"config.yml":   "$include: host.yml\nport: 80\n",
+		"tagged.yml":   "port: 80\nextra: !include host.yml\n",
+		"config.jsonc": "{\n  // the port\n  \"port\": 80\n}\n",
+		"config.json5": "{\n  // the port\n  port: 80,\n}\n",
+		// .json files with comments allowed by WithJSONComments.
+		"config.json":       "{\n  // the port\n  \"port\": 80\n}\n",
+		"config.hcl":        "port = 80\n",
+		"config.ini":        "port = 80\n",
+		"config.properties": "port = 80\n",
+		"config.env":        "PORT=80\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		_ = ioutil.WriteFile(path, []byte(content), 0644)
+
+		var warnings []string
+		configLoader, err := configuro.NewConfig(
+			configuro.WithoutLoadFromEnvVars(),
+			configuro.WithoutLoadDotEnv(),
+			configuro.WithLoadFromConfigFile(path, true),
+			configuro.WithoutEnvConfigPathOverload(),
+			configuro.WithMigrations(migrations),
+			configuro.WithMigrationsWriteBack(),
+			configuro.WithJSONComments(),
+			configuro.WithWarningLogger(func(warning string) {
+				warnings = append(warnings, warning)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		obj := &Obj{}
+		err = configLoader.Load(obj)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if obj.Version != 1 || obj.Port != 8080 {
+			t.Fatalf("%s: expected the config to be migrated, loaded: %+v", name, obj)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "not written back") {
+			t.Fatalf("%s: expected a not written back warning, got: %v", name, warnings)
+		}
+
+		saved, _ := ioutil.ReadFile(path)
+		if string(saved) != content {
+			t.Fatalf("%s: expected the file not to be written back, got:\n%s", name, saved)
+		}
+	}
+}
+
+func TestDeprecatedKeys(t *testing.T) {
+	type Database struct {
+		Host string `deprecated:"db_host"`
+		Port int
+	}
+
+	type Obj struct {
+		Database Database
+		Listen   string
+	}
+
+	configFileYaml, err := ioutil.TempFile("", "TestDeprecatedKeys*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	_, _ = configFileYaml.Write([]byte(`
+db_host: localhost
+database:
+  port: 5432
+`))
+
+	_ = os.Setenv("DEPRECATED_SERVER_ADDR", ":8080")
+	defer os.Unsetenv("DEPRECATED_SERVER_ADDR")
+
+	var warnings []string
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("DEPRECATED"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithKeyAliases(map[string]string{"server.addr": "listen"}),
+		configuro.WithWarningLogger(func(warning string) {
+			warnings = append(warnings, warning)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Obj{Database: Database{Host: "localhost", Port: 5432}, Listen: ":8080"}
+	if !reflect.DeepEqual(*obj, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj, expected)
+	}
+
+	expectedWarnings := []string{
+		fmt.Sprintf("config key \"db_host\" set by file %s is deprecated, use \"database.host\" instead", configFileYaml.Name()),
+		"config key \"server.addr\" set by env DEPRECATED_SERVER_ADDR is deprecated, use \"listen\" instead",
+	}
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Fatalf("Logged warnings doesn't equal expected warnings. logged: %v, expected: %v", warnings, expectedWarnings)
+	}
+
+	// Setting both keys to different values is a conflict.
+	_ = os.Setenv("DEPRECATED_LISTEN", ":9090")
+	defer os.Unsetenv("DEPRECATED_LISTEN")
+
+	err = configLoader.Load(&Obj{})
+	var errConflict *configuro.ErrKeyConflict
+	if !errors.As(err, &errConflict) {
+		t.Fatalf("expected a key conflict error, got: %v", err)
+	}
+
+	// Setting both keys to the same value is allowed.
+	_ = os.Setenv("DEPRECATED_LISTEN", ":8080")
+
+	err = configLoader.Load(&Obj{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeprecatedKeysRecursiveStruct(t *testing.T) {
+	type Node struct {
+		Name string `deprecated:"title"`
+		Next *Node
+	}
+
+	configFileYaml, err := ioutil.TempFile("", "TestDeprecatedKeysRecursiveStruct*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	_, _ = configFileYaml.Write([]byte(`
+title: root
+next:
+  name: child
+`))
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithWarningLogger(func(string) {}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := &Node{}
+	err = configLoader.Load(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Node{Name: "root", Next: &Node{Name: "child"}}
+	if !reflect.DeepEqual(*node, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", node, expected)
+	}
+}
+
+func TestValueIsSetAndSource(t *testing.T) {
+	type Logger struct {
+		Debug configuro.Value[bool]
+		Level configuro.Value[string]
+	}
+
+	type Obj struct {
+		Logger  Logger
+		Port    configuro.Value[int]
+		Tags    configuro.Value[[]string]
+		Workers *configuro.Value[int]
+	}
+
+	configFileYaml, err := ioutil.TempFile("", "TestValueIsSetAndSource*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	_, _ = configFileYaml.Write([]byte(`
+logger:
+  debug: false
+port: 80
+tags: [a, b]
+`))
+
+	_ = os.Setenv("VALUE_PORT", "8080")
+	defer os.Unsetenv("VALUE_PORT")
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("VALUE"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !obj.Logger.Debug.IsSet() || obj.Logger.Debug.Get() || !obj.Logger.Debug.Or(true) == obj.Logger.Debug.Get() {
+		t.Fatalf("expected logger.debug to be set to false, got: %v (set: %v)", obj.Logger.Debug.Get(), obj.Logger.Debug.IsSet())
+	}
+	if obj.Logger.Debug.Source() != (configuro.Source{Kind: configuro.SourceFile, Name: configFileYaml.Name()}) {
+		t.Fatalf("unexpected logger.debug source: %v", obj.Logger.Debug.Source())
+	}
+
+	if obj.Logger.Level.IsSet() || obj.Logger.Level.Or("info") != "info" || obj.Logger.Level.Source().Kind != configuro.SourceNone {
+		t.Fatalf("expected logger.level to be unset, got: %v (set: %v)", obj.Logger.Level.Get(), obj.Logger.Level.IsSet())
+	}
+
+	if obj.Port.Get() != 8080 || obj.Port.Source() != (configuro.Source{Kind: configuro.SourceEnv, Name: "VALUE_PORT"}) {
+		t.Fatalf("expected port to be set to 8080 from env, got: %v (source: %v)", obj.Port.Get(), obj.Port.Source())
+	}
+
+	if !reflect.DeepEqual(obj.Tags.Get(), []string{"a", "b"}) {
+		t.Fatalf("unexpected tags: %v", obj.Tags.Get())
+	}
+
+	if obj.Workers != nil {
+		t.Fatalf("expected workers to be nil, got: %v", obj.Workers)
+	}
+
+	if !configLoader.IsSet("logger.debug") || configLoader.IsSet("logger.level") || !configLoader.IsSet("PORT") {
+		t.Fatalf("config IsSet doesn't match loaded values")
+	}
+	if configLoader.Source("port").String() != "env VALUE_PORT" {
+		t.Fatalf("unexpected port source: %v", configLoader.Source("port"))
+	}
+
+	logger := &Logger{}
+	err = configLoader.LoadKey("logger", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !logger.Debug.IsSet() || logger.Debug.Source().Kind != configuro.SourceFile || logger.Level.IsSet() {
+		t.Fatalf("LoadKey loaded unexpected values: %v", logger)
+	}
+}
+
+func TestPreserveMapKeysCase(t *testing.T) {
+	type Obj struct {
+		Headers  map[string]string
+		Tenants  map[string]map[string]int
+		Features map[string]interface{}
+		Database struct {
+			Host string
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ext     string
+		content string
+	}{
+		{name: "yaml", ext: ".yml", content: `
+headers:
+  X-Request-ID: abc
+tenants:
+  TenantA:
+    MaxUsers: 10
+features:
+  NewUI:
+    BetaFlag: true
+DATABASE:
+  HOST: localhost
+`},
+		{name: "json", ext: ".json", content: `{
+  "headers": {"X-Request-ID": "abc"},
+  "tenants": {"TenantA": {"MaxUsers": 10}},
+  "features": {"NewUI": {"BetaFlag": true}},
+  "DATABASE": {"HOST": "localhost"}
+}`},
+		{name: "toml", ext: ".toml", content: `
+[headers]
+X-Request-ID = "abc"
+[tenants.TenantA]
+MaxUsers = 10
+[features.NewUI]
+BetaFlag = true
+[DATABASE]
+HOST = "localhost"
+`},
+	}
+
+	_ = os.Setenv("CASE_HEADERS_Content-Type", "json")
+	_ = os.Setenv("CASE_HEADERS_X-REQUEST-ID", "env")
+	defer func() {
+		_ = os.Unsetenv("CASE_HEADERS_Content-Type")
+		_ = os.Unsetenv("CASE_HEADERS_X-REQUEST-ID")
+	}()
+
+	expected := Obj{
+		Headers:  map[string]string{"X-Request-ID": "env", "Content-Type": "json"},
+		Tenants:  map[string]map[string]int{"TenantA": {"MaxUsers": 10}},
+		Features: map[string]interface{}{"NewUI": map[string]interface{}{"BetaFlag": true}},
+	}
+	expected.Database.Host = "localhost"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile, err := ioutil.TempFile("", "TestPreserveMapKeysCase*"+tt.ext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				configFile.Close()
+				os.RemoveAll(configFile.Name())
+			}()
+			_, _ = configFile.Write([]byte(tt.content))
+
+			configLoader, err := configuro.NewConfig(
+				configuro.WithLoadFromEnvVars("CASE"),
+				configuro.WithoutLoadDotEnv(),
+				configuro.WithLoadFromConfigFile(configFile.Name(), true),
+				configuro.WithoutEnvConfigPathOverload(),
+				configuro.WithPreserveMapKeysCase(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := &Obj{}
+			err = configLoader.Load(obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(*obj, expected) {
+				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj, expected)
+			}
+
+			tenants := make(map[string]map[string]int)
+			err = configLoader.LoadKey("tenants", &tenants)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tenants, expected.Tenants) {
+				t.Fatalf("LoadKey Loaded Values doesn't equal expected values. loaded: %v, expected: %v", tenants, expected.Tenants)
+			}
+		})
+	}
+}
+
+func TestLoadFromIndexedEnvVars(t *testing.T) {
+	type Host struct {
+		Addr string
+		Port int
+	}
+
+	type Database struct {
+		Hosts []Host
+		Tags  []string
+	}
+
+	type Obj struct {
+		Database Database
+		WordMap  map[string]string `config:"word_map"`
+	}
+
+	configFileYaml, err := ioutil.TempFile("", "TestLoadFromIndexedEnvVars*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	_, _ = configFileYaml.Write([]byte(`
+database:
+  hosts:
+    - addr: db-1
+      port: 5432
+    - addr: db-2
+      port: 5432
+  tags: [a, b]
+word_map:
+  key1: file
+  key2: file
+`))
+
+	_ = os.Setenv("INDEXED_DATABASE_HOSTS_0_PORT", "6543")
+	_ = os.Setenv("INDEXED_DATABASE_HOSTS_2_ADDR", "db-3")
+	_ = os.Setenv("INDEXED_DATABASE_TAGS_1", "c")
+	_ = os.Setenv("INDEXED_WORD__MAP_KEY1", "env")
+	_ = os.Setenv("INDEXED_WORD__MAP_KEY3", "env")
+	defer func() {
+		_ = os.Unsetenv("INDEXED_DATABASE_HOSTS_0_PORT")
+		_ = os.Unsetenv("INDEXED_DATABASE_HOSTS_2_ADDR")
+		_ = os.Unsetenv("INDEXED_DATABASE_TAGS_1")
+		_ = os.Unsetenv("INDEXED_WORD__MAP_KEY1")
+		_ = os.Unsetenv("INDEXED_WORD__MAP_KEY3")
+	}()
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("INDEXED"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Obj{
+		Database: Database{
+			Hosts: []Host{{Addr: "db-1", Port: 6543}, {Addr: "db-2", Port: 5432}, {Addr: "db-3"}},
+			Tags:  []string{"a", "c"},
+		},
+		WordMap: map[string]string{"key1": "env", "key2": "file", "key3": "env"},
+	}
+	if !reflect.DeepEqual(*obj, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj, expected)
+	}
+
+	database := &Database{}
+	err = configLoader.LoadKey("database", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*database, expected.Database) {
+		t.Fatalf("LoadKey Loaded Values doesn't equal expected values. loaded: %v, expected: %v", database, expected.Database)
+	}
+
+	// Indexed values are merged into a JSON encoded slice too.
+	_ = os.Setenv("INDEXED_DATABASE_HOSTS", `[{"addr": "json-1", "port": 1}]`)
+	defer os.Unsetenv("INDEXED_DATABASE_HOSTS")
+
+	obj = &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHosts := []Host{{Addr: "json-1", Port: 6543}, {}, {Addr: "db-3"}}
+	if !reflect.DeepEqual(obj.Database.Hosts, expectedHosts) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj.Database.Hosts, expectedHosts)
+	}
+}
+
+func TestLoadFromEnvTags(t *testing.T) {
+	type Database struct {
+		URL      string `env:"ENVTAG_DATABASE_URL,ENVTAG_DB_URL"`
+		Username string `env:"ENVTAG_DB_USERNAME"`
+		Password string
+	}
+
+	type Obj struct {
+		Port     int `env:"ENVTAG_PORT"`
+		Database *Database
+	}
+
+	_ = os.Unsetenv("ENVTAG_DATABASE_URL")
+	_ = os.Setenv("ENVTAG_DB_URL", "postgres://alias")
+	_ = os.Setenv("ENVTAG_PORT", "8080")
+	_ = os.Setenv("ENVTAG_DB_USERNAME", "explicit")
+	_ = os.Setenv("PREFIX_DATABASE_USERNAME", "prefixed")
+	_ = os.Setenv("PREFIX_DATABASE_PASSWORD", "prefixed")
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("PREFIX"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithoutLoadFromConfigFile(),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Obj{Port: 8080, Database: &Database{URL: "postgres://alias", Username: "explicit", Password: "prefixed"}}
+	if !reflect.DeepEqual(*obj, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj, expected)
+	}
+
+	database := &Database{}
+	err = configLoader.LoadKey("database", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*database, *expected.Database) {
+		t.Fatalf("LoadKey Loaded Values doesn't equal expected values. loaded: %v, expected: %v", database, expected.Database)
+	}
+}
+
+func TestLoadFromEnvTagsRecursiveStruct(t *testing.T) {
+	type Node struct {
+		Name string `env:"ENVTAG_NODE_NAME"`
+		Next *Node
+	}
+
+	_ = os.Setenv("ENVTAG_NODE_NAME", "root")
+	defer os.Unsetenv("ENVTAG_NODE_NAME")
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("PREFIX"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithoutLoadFromConfigFile(),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := &Node{}
+	err = configLoader.Load(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Name != "root" || node.Next != nil {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %+v", node)
+	}
+}
+
 func TestLoadDotEnv(t *testing.T) {
 
-	// Clear Env that may be set up by previous tests. So that .env values are not overridden
+	// Clear Env that may be set up by previous tests. So that .env values are not overridden
+	os.Clearenv()
+
+	dotEnvFile, err := ioutil.TempFile("", "*.env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		dotEnvFile.Close()
+		os.RemoveAll(dotEnvFile.Name())
+	}()
+
+	// Write Config to File
+	dotEnvFile.WriteString(`
+PREFIX_NESTED_KEY_A: XXX
+PREFIX_NESTED_KEY_B: YYY
+#PREFIX_NESTED_KEY_B: XYZ
+PREFIX_NESTED_EMPTY:
+#PREFIX_NESTED_EMPTY: FD
+CONFIG_NESTED_KEY_A: A
+CONFIG_NESTED_KEY_B: B
+CONFIG_NESTED_KEY_EMPTY:
+    `)
+
+	envOnlyWithPrefix, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("PREFIX"),
+		configuro.WithLoadDotEnv(dotEnvFile.Name()),
+		configuro.WithoutLoadFromConfigFile(),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []test{
+		{name: "withPrefix", config: envOnlyWithPrefix, expected: Example{Nested{Key: Key{
+			A:     "XXX",
+			B:     "YYY",
+			EMPTY: "",
+		}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			example := &Example{}
+			err := test.config.Load(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if example.Nested.Key.A != test.expected.Nested.Key.A ||
+				example.Nested.Key.B != test.expected.Nested.Key.B ||
+				example.Nested.Key.EMPTY != test.expected.Nested.Key.EMPTY {
+				t.Fatal("Loaded Values doesn't equal expected values.")
+			}
+		})
+	}
+}
+
+func TestLoadFromFileThatDoesntExist(t *testing.T) {
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("XXX"),
+		configuro.WithLoadDotEnv(""),
+		configuro.WithLoadFromConfigFile("filethatdoesntexist.yml", false),
+		configuro.WithEnvConfigPathOverload(""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []test{
+		{name: "LoadFromFileThatDoesntExist", config: configLoader, expected: Example{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			example := &Example{}
+			err := test.config.Load(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if example.Nested.Key.A != test.expected.Nested.Key.A ||
+				example.Nested.Key.B != test.expected.Nested.Key.B ||
+				example.Nested.Key_A != test.expected.Nested.Key_A ||
+				example.Nested.Key_X != test.expected.Nested.Key_X {
+				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFromFileThatDoesntExistError(t *testing.T) {
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromConfigFile("filethatdoesntexist.yml", true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = configLoader.Load(&Example{})
+
+	if err == nil {
+		t.Fatal("Load should raise error if ErrIfFileNotFound was set to true")
+	}
+	if !strings.Contains(err.Error(), "error config file not found") {
+		t.Fatal("Load should raise not found error if ErrIfFileNotFound was set to true")
+	}
+}
+
+func TestLoadFromFileOnly(t *testing.T) {
+	configFileYaml, err := ioutil.TempFile("", "TestLoadFromFileOnly*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	// Write Config to File
+	configFileYaml.WriteString(`
+nested:
+    key:
+        a: A
+        b: B
+    key_a:
+        a: AA
+        b: AB
+    key-b:
+        a: BA
+        b: BB
+    `)
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("X"),
+		configuro.WithLoadDotEnv(""),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), false),
+		configuro.WithEnvConfigPathOverload(""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []test{
+		{name: "LoadFromFile", config: configLoader, expected: Example{
+			Nested: Nested{
+				Key: Key{
+					A: "A",
+					B: "B",
+				},
+				Key_A: &Key{
+					A: "AA",
+					B: "AB",
+				},
+				Key_X: &Key{
+					A: "BA",
+					B: "BB",
+				},
+			},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			example := &Example{}
+			err := test.config.Load(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if example.Nested.Key.A != test.expected.Nested.Key.A ||
+				example.Nested.Key.B != test.expected.Nested.Key.B ||
+				example.Nested.Key_A.A != test.expected.Nested.Key_A.A ||
+				example.Nested.Key_A.B != test.expected.Nested.Key_A.B ||
+				example.Nested.Key_X.A != test.expected.Nested.Key_X.A ||
+				example.Nested.Key_X.B != test.expected.Nested.Key_X.B {
+				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, test.expected)
+			}
+		})
+	}
+}
+
+func TestConfigSearchPaths(t *testing.T) {
 	os.Clearenv()
 
-	dotEnvFile, err := ioutil.TempFile("", "*.env")
+	etcDir, err := ioutil.TempDir("", "TestConfigSearchPathsEtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userDir, err := ioutil.TempDir("", "TestConfigSearchPathsUser")
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer func() {
-		dotEnvFile.Close()
-		os.RemoveAll(dotEnvFile.Name())
+		os.RemoveAll(etcDir)
+		os.RemoveAll(userDir)
+	}()
+
+	err = ioutil.WriteFile(filepath.Join(etcDir, "config.yml"), []byte(`
+nested:
+    key:
+        a: etcA
+        b: etcB
+    `), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(userDir, "config.json"), []byte(`{"nested": {"key": {"a": "userA"}}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = os.Setenv("USER_CONFIG_DIR", userDir)
+
+	firstMatch, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithConfigSearchPaths("app", "$USER_CONFIG_DIR", etcDir),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mergeAll, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithConfigSearchPaths("app", "$USER_CONFIG_DIR", etcDir),
+		configuro.WithConfigSearchMergeAll(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notFound, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithConfigSearchPaths("app", filepath.Join(etcDir, "doesntexist")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		config    *configuro.Config
+		expected  Key
+		filesUsed []string
+	}{
+		{name: "firstMatch", config: firstMatch, expected: Key{A: "userA"},
+			filesUsed: []string{filepath.Join(userDir, "config.json")}},
+		{name: "mergeAll", config: mergeAll, expected: Key{A: "userA", B: "etcB"},
+			filesUsed: []string{filepath.Join(userDir, "config.json"), filepath.Join(etcDir, "config.yml")}},
+		{name: "notFound", config: notFound, expected: Key{}, filesUsed: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			example := &Example{}
+			err := test.config.Load(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if example.Nested.Key.A != test.expected.A || example.Nested.Key.B != test.expected.B {
+				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example.Nested.Key, test.expected)
+			}
+
+			filesUsed := test.config.ConfigFilesUsed()
+			if len(filesUsed) != len(test.filesUsed) {
+				t.Fatalf("ConfigFilesUsed() = %v, expected: %v", filesUsed, test.filesUsed)
+			}
+			for i := range filesUsed {
+				if filesUsed[i] != test.filesUsed[i] {
+					t.Fatalf("ConfigFilesUsed() = %v, expected: %v", filesUsed, test.filesUsed)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFromHCLFile(t *testing.T) {
+	configFileHCL, err := ioutil.TempFile("", "TestLoadFromHCLFile*.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		configFileHCL.Close()
+		os.RemoveAll(configFileHCL.Name())
 	}()
 
 	// Write Config to File
-	dotEnvFile.WriteString(`
-PREFIX_NESTED_KEY_A: XXX
-PREFIX_NESTED_KEY_B: YYY
-#PREFIX_NESTED_KEY_B: XYZ
-PREFIX_NESTED_EMPTY:
-#PREFIX_NESTED_EMPTY: FD
-CONFIG_NESTED_KEY_A: A
-CONFIG_NESTED_KEY_B: B
-CONFIG_NESTED_KEY_EMPTY:
+	configFileHCL.WriteString(`
+nested {
+    number = 10
+    numberList1 = [1, 2, 3]
+
+    key {
+        a = "A"
+        b = "B"
+    }
+
+    key_a {
+        a = "AA"
+        b = "AB"
+    }
+
+    key-b {
+        a = "BA"
+        b = "BB"
+    }
+
+    keyList {
+        a = "L1"
+    }
+
+    keyList {
+        a = "L2"
+    }
+
+    keyMap "one" {
+        a = "M1"
+    }
+}
     `)
 
-	envOnlyWithPrefix, err := configuro.NewConfig(
-		configuro.WithLoadFromEnvVars("PREFIX"),
-		configuro.WithLoadDotEnv(dotEnvFile.Name()),
-		configuro.WithoutLoadFromConfigFile(),
-		configuro.WithoutEnvConfigPathOverload(),
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("X"),
+		configuro.WithLoadDotEnv(""),
+		configuro.WithLoadFromConfigFile(configFileHCL.Name(), true),
+		configuro.WithEnvConfigPathOverload(""),
 	)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	tests := []test{
-		{name: "withPrefix", config: envOnlyWithPrefix, expected: Example{Nested{Key: Key{
-			A:     "XXX",
-			B:     "YYY",
-			EMPTY: "",
-		}}}},
+		{name: "LoadFromHCLFile", config: configLoader, expected: Example{
+			Nested: Nested{
+				Key: Key{
+					A: "A",
+					B: "B",
+				},
+				Key_A: &Key{
+					A: "AA",
+					B: "AB",
+				},
+				Key_X: &Key{
+					A: "BA",
+					B: "BB",
+				},
+				Number:      10,
+				NumberList1: []int{1, 2, 3},
+				KeyList:     []Key{{A: "L1"}, {A: "L2"}},
+				KeyMap:      map[string]Key{"one": {A: "M1"}},
+			},
+		}},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -229,26 +2083,198 @@ CONFIG_NESTED_KEY_EMPTY:
 
 			if example.Nested.Key.A != test.expected.Nested.Key.A ||
 				example.Nested.Key.B != test.expected.Nested.Key.B ||
-				example.Nested.Key.EMPTY != test.expected.Nested.Key.EMPTY {
-				t.Fatal("Loaded Values doesn't equal expected values.")
+				example.Nested.Key_A.A != test.expected.Nested.Key_A.A ||
+				example.Nested.Key_A.B != test.expected.Nested.Key_A.B ||
+				example.Nested.Key_X.A != test.expected.Nested.Key_X.A ||
+				example.Nested.Key_X.B != test.expected.Nested.Key_X.B ||
+				example.Nested.Number != test.expected.Nested.Number ||
+				!equalSlice(example.Nested.NumberList1, test.expected.Nested.NumberList1) ||
+				!reflect.DeepEqual(example.Nested.KeyList, test.expected.Nested.KeyList) ||
+				!reflect.DeepEqual(example.Nested.KeyMap, test.expected.Nested.KeyMap) {
+				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, test.expected)
 			}
 		})
 	}
 }
 
-func TestLoadFromFileThatDoesntExist(t *testing.T) {
-	configLoader, err := configuro.NewConfig(
-		configuro.WithLoadFromEnvVars("XXX"),
-		configuro.WithLoadDotEnv(""),
-		configuro.WithLoadFromConfigFile("filethatdoesntexist.yml", false),
-		configuro.WithEnvConfigPathOverload(""),
+func TestLoadFromINIAndPropertiesFiles(t *testing.T) {
+	configFileINI, err := ioutil.TempFile("", "TestLoadFromINIAndPropertiesFiles*.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configFileProperties, err := ioutil.TempFile("", "TestLoadFromINIAndPropertiesFiles*.properties")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		configFileINI.Close()
+		configFileProperties.Close()
+		os.RemoveAll(configFileINI.Name())
+		os.RemoveAll(configFileProperties.Name())
+	}()
+
+	// Write Config to File
+	configFileINI.WriteString(`
+[nested]
+number = 10
+numberList1 = 1,2,3
+
+[nested.key]
+a = A
+b = B
+
+[nested.key-b]
+a = BA
+b = BB
+    `)
+
+	configFileProperties.WriteString(`
+nested.number = 10
+nested.numberList1 = 1,2,3
+nested.key.a = A
+nested.key.b = B
+nested.key-b.a = BA
+nested.key-b.b = ${NOT_EXPANDED_BY_PROPERTIES}
+    `)
+
+	iniLoader, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithoutExpandEnvVars(),
+		configuro.WithLoadFromConfigFile(configFileINI.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	propertiesLoader, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithoutExpandEnvVars(),
+		configuro.WithLoadFromConfigFile(configFileProperties.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
 	)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	tests := []test{
-		{name: "LoadFromFileThatDoesntExist", config: configLoader, expected: Example{}},
+		{name: "ini", config: iniLoader, expected: Example{
+			Nested: Nested{
+				Key:         Key{A: "A", B: "B"},
+				Key_X:       &Key{A: "BA", B: "BB"},
+				Number:      10,
+				NumberList1: []int{1, 2, 3},
+			},
+		}},
+		{name: "properties", config: propertiesLoader, expected: Example{
+			Nested: Nested{
+				Key:         Key{A: "A", B: "B"},
+				Key_X:       &Key{A: "BA", B: "${NOT_EXPANDED_BY_PROPERTIES}"},
+				Number:      10,
+				NumberList1: []int{1, 2, 3},
+			},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			example := &Example{}
+			err := test.config.Load(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if example.Nested.Key.A != test.expected.Nested.Key.A ||
+				example.Nested.Key.B != test.expected.Nested.Key.B ||
+				example.Nested.Key_X.A != test.expected.Nested.Key_X.A ||
+				example.Nested.Key_X.B != test.expected.Nested.Key_X.B ||
+				example.Nested.Number != test.expected.Nested.Number ||
+				!equalSlice(example.Nested.NumberList1, test.expected.Nested.NumberList1) {
+				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFromJSONCAndJSON5Files(t *testing.T) {
+	files := map[string]string{
+		"*.jsonc": `
+{
+    // Comments are allowed in .jsonc
+    "nested": {
+        "key": {
+            "a": "A", /* inline comment */
+            "b": "B // not a comment",
+        },
+        "numberList1": [1, 2, 3,],
+    },
+}
+    `,
+		"*.json5": `
+{
+    // JSON5 allow unquoted keys, single quoted strings, and hex numbers.
+    nested: {
+        key: {
+            a: 'A',
+            b: 'B // not a comment',
+        },
+        number: 0x10,
+        numberList1: [1, +2, 3.,],
+    },
+}
+    `,
+		"*.json": `
+{
+    // Comments are allowed in .json using WithJSONComments()
+    "nested": {
+        "key": {"a": "A", "b": "B // not a comment"},
+        "numberList1": [1, 2, 3],
+    }
+}
+    `,
+	}
+
+	loaders := make(map[string]*configuro.Config)
+	for pattern, content := range files {
+		file, err := ioutil.TempFile("", "TestLoadFromJSONCAndJSON5Files"+pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			file.Close()
+			os.RemoveAll(file.Name())
+		}()
+		file.WriteString(content)
+
+		loaders[pattern], err = configuro.NewConfig(
+			configuro.WithoutLoadFromEnvVars(),
+			configuro.WithoutLoadDotEnv(),
+			configuro.WithLoadFromConfigFile(file.Name(), true),
+			configuro.WithoutEnvConfigPathOverload(),
+			configuro.WithJSONComments(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []test{
+		{name: "jsonc", config: loaders["*.jsonc"], expected: Example{Nested: Nested{
+			Key:         Key{A: "A", B: "B // not a comment"},
+			NumberList1: []int{1, 2, 3},
+		}}},
+		{name: "json5", config: loaders["*.json5"], expected: Example{Nested: Nested{
+			Key:         Key{A: "A", B: "B // not a comment"},
+			Number:      16,
+			NumberList1: []int{1, 2, 3},
+		}}},
+		{name: "jsonWithComments", config: loaders["*.json"], expected: Example{Nested: Nested{
+			Key:         Key{A: "A", B: "B // not a comment"},
+			NumberList1: []int{1, 2, 3},
+		}}},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -258,104 +2284,196 @@ func TestLoadFromFileThatDoesntExist(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if example.Nested.Key.A != test.expected.Nested.Key.A ||
-				example.Nested.Key.B != test.expected.Nested.Key.B ||
-				example.Nested.Key_A != test.expected.Nested.Key_A ||
-				example.Nested.Key_X != test.expected.Nested.Key_X {
-				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, test.expected)
+			if example.Nested.Key.A != test.expected.Nested.Key.A ||
+				example.Nested.Key.B != test.expected.Nested.Key.B ||
+				example.Nested.Number != test.expected.Nested.Number ||
+				!equalSlice(example.Nested.NumberList1, test.expected.Nested.NumberList1) {
+				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, test.expected)
+			}
+		})
+	}
+}
+
+func TestJSONParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		position string
+	}{
+		{
+			name: "missing comma",
+			content: `{
+    // a comment that shift offsets
+    "nested": {
+        "key": {"a": "A" "b": "B"}
+    }
+}`,
+			position: "line 4, column 26",
+		},
+		{
+			// A comma without a value before it is not a trailing comma.
+			name: "stray comma",
+			content: `{
+    "nested": {
+        "key": {"a": ,}
+    }
+}`,
+			position: "line 3, column 22",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configFileJSONC, err := ioutil.TempFile("", "TestJSONParseErrorPosition*.jsonc")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				configFileJSONC.Close()
+				os.RemoveAll(configFileJSONC.Name())
+			}()
+
+			configFileJSONC.WriteString(test.content)
+
+			configLoader, err := configuro.NewConfig(
+				configuro.WithoutLoadFromEnvVars(),
+				configuro.WithoutLoadDotEnv(),
+				configuro.WithLoadFromConfigFile(configFileJSONC.Name(), true),
+				configuro.WithoutEnvConfigPathOverload(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = configLoader.Load(&Example{})
+			if err == nil {
+				t.Fatal("Load should fail on invalid JSONC")
+			}
+			if !strings.Contains(err.Error(), test.position) {
+				t.Fatalf("parse error should contain error position, got: %v", err)
 			}
 		})
 	}
 }
 
-func TestLoadFromFileThatDoesntExistError(t *testing.T) {
+func TestLoadFromDotEnvConfigFile(t *testing.T) {
+	configFileDotEnv, err := ioutil.TempFile("", "TestLoadFromDotEnvConfigFile*.env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileDotEnv.Close()
+		os.RemoveAll(configFileDotEnv.Name())
+	}()
+
+	// Write Config to File
+	configFileDotEnv.WriteString(`
+DOTENVFILE_NESTED_KEY_A=A
+DOTENVFILE_NESTED_KEY_B=B
+DOTENVFILE_NESTED_KEY__A_A=AA
+DOTENVFILE_NESTED_NUMBERLIST1=1,2,3
+OTHER_NESTED_KEY_C=NOT_LOADED
+    `)
+
+	_ = os.Setenv("DOTENVFILE_NESTED_KEY_B", "OS")
+
 	configLoader, err := configuro.NewConfig(
-		configuro.WithLoadFromConfigFile("filethatdoesntexist.yml", true),
+		configuro.WithLoadFromEnvVars("DOTENVFILE"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileDotEnv.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
 	)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = configLoader.Load(&Example{})
-
-	if err == nil {
-		t.Fatal("Load should raise error if ErrIfFileNotFound was set to true")
+	example := &Example{}
+	err = configLoader.Load(example)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if !strings.Contains(err.Error(), "error config file not found") {
-		t.Fatal("Load should raise not found error if ErrIfFileNotFound was set to true")
+
+	expected := Example{Nested: Nested{
+		Key:         Key{A: "A", B: "OS"},
+		Key_A:       &Key{A: "AA"},
+		NumberList1: []int{1, 2, 3},
+	}}
+	if example.Nested.Key.A != expected.Nested.Key.A ||
+		example.Nested.Key.B != expected.Nested.Key.B ||
+		example.Nested.Key.C != expected.Nested.Key.C ||
+		example.Nested.Key_A == nil ||
+		example.Nested.Key_A.A != expected.Nested.Key_A.A ||
+		!equalSlice(example.Nested.NumberList1, expected.Nested.NumberList1) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, expected)
 	}
 }
 
-func TestLoadFromFileOnly(t *testing.T) {
-	configFileYaml, err := ioutil.TempFile("", "TestLoadFromFileOnly*.yml")
+func TestConfigFileIncludes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestConfigFileIncludes")
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer os.RemoveAll(dir)
 
-	defer func() {
-		configFileYaml.Close()
-		os.RemoveAll(configFileYaml.Name())
-	}()
-
-	// Write Config to File
-	configFileYaml.WriteString(`
+	files := map[string]string{
+		"config.yml": `
 nested:
-    key:
-        a: A
-        b: B
-    key_a:
-        a: AA
-        b: AB
-    key-b:
-        a: BA
-        b: BB
-    `)
+    key: !include key.yml
+    key_a: !include key_a.json
+    number: 1
+`,
+		"key.yml":          "a: A\nb: B\n",
+		"key_a.json":       `{"$include": "conf.d/*.json", "b": "OWN"}`,
+		"conf.d/1.json":    `{"a": "FIRST", "b": "FIRST"}`,
+		"conf.d/2.json":    `{"a": "SECOND"}`,
+		"cycle.yml":        "nested: !include cycle_nested.yml\n",
+		"cycle_nested.yml": "key: !include cycle.yml\n",
+		"missing.toml":     "\"$include\" = \"doesntexist.toml\"\n",
+	}
+	err = os.MkdirAll(filepath.Join(dir, "conf.d"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
 
-	configLoader, err := configuro.NewConfig(
-		configuro.WithLoadFromEnvVars("X"),
-		configuro.WithLoadDotEnv(""),
-		configuro.WithLoadFromConfigFile(configFileYaml.Name(), false),
-		configuro.WithEnvConfigPathOverload(""),
-	)
+	newLoader := func(file string) *configuro.Config {
+		configLoader, err := configuro.NewConfig(
+			configuro.WithoutLoadFromEnvVars(),
+			configuro.WithoutLoadDotEnv(),
+			configuro.WithLoadFromConfigFile(filepath.Join(dir, file), true),
+			configuro.WithoutEnvConfigPathOverload(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return configLoader
+	}
+
+	example := &Example{}
+	err = newLoader("config.yml").Load(example)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	tests := []test{
-		{name: "LoadFromFile", config: configLoader, expected: Example{
-			Nested: Nested{
-				Key: Key{
-					A: "A",
-					B: "B",
-				},
-				Key_A: &Key{
-					A: "AA",
-					B: "AB",
-				},
-				Key_X: &Key{
-					A: "BA",
-					B: "BB",
-				},
-			},
-		}},
+	if example.Nested.Key.A != "A" || example.Nested.Key.B != "B" ||
+		example.Nested.Key_A == nil || example.Nested.Key_A.A != "SECOND" || example.Nested.Key_A.B != "OWN" ||
+		example.Nested.Number != 1 {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v", example)
 	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			example := &Example{}
-			err := test.config.Load(example)
-			if err != nil {
-				t.Fatal(err)
-			}
 
-			if example.Nested.Key.A != test.expected.Nested.Key.A ||
-				example.Nested.Key.B != test.expected.Nested.Key.B ||
-				example.Nested.Key_A.A != test.expected.Nested.Key_A.A ||
-				example.Nested.Key_A.B != test.expected.Nested.Key_A.B ||
-				example.Nested.Key_X.A != test.expected.Nested.Key_X.A ||
-				example.Nested.Key_X.B != test.expected.Nested.Key_X.B {
-				t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example, test.expected)
-			}
-		})
+	err = newLoader("cycle.yml").Load(&Example{})
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Fatalf("Load should fail on include cycle, got: %v", err)
+	}
+
+	err = newLoader("missing.toml").Load(&Example{})
+	if err == nil || !strings.Contains(err.Error(), "doesntexist.toml") {
+		t.Fatalf("Load should fail on missing included file, got: %v", err)
 	}
 }
 
@@ -631,6 +2749,286 @@ nested:
 	}
 }
 
+func TestExpandEnvVarSyntax(t *testing.T) {
+	_ = os.Setenv("EXPAND_SET", "set")
+	_ = os.Setenv("EXPAND_EMPTY", "")
+	_ = os.Unsetenv("EXPAND_UNSET")
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "defaultWithURL", value: "${EXPAND_UNSET|postgres://localhost:5432/db?sslmode=disable}", expected: "postgres://localhost:5432/db?sslmode=disable"},
+		{name: "defaultWithSpaces", value: "${EXPAND_UNSET|hello world}", expected: "hello world"},
+		{name: "pipeDefaultOnEmpty", value: "${EXPAND_EMPTY|default}", expected: ""},
+		{name: "colonDash", value: "${EXPAND_EMPTY:-default}", expected: "default"},
+		{name: "colonDashSet", value: "${EXPAND_SET:-default}", expected: "set"},
+		{name: "dash", value: "${EXPAND_EMPTY-default}", expected: ""},
+		{name: "dashUnset", value: "${EXPAND_UNSET-default}", expected: "default"},
+		{name: "colonPlus", value: "${EXPAND_SET:+alternate}", expected: "alternate"},
+		{name: "colonPlusEmpty", value: "${EXPAND_EMPTY:+alternate}", expected: ""},
+		{name: "nestedDefaults", value: "${EXPAND_UNSET|${EXPAND_UNSET_TOO|${EXPAND_SET}}}", expected: "set"},
+		{name: "escaping", value: "$${EXPAND_SET} costs 5$", expected: "${EXPAND_SET} costs 5$"},
+		{name: "unsetLeftAsIs", value: "${EXPAND_UNSET}", expected: "${EXPAND_UNSET}"},
+		{name: "colonQuestion", value: "${EXPAND_EMPTY:?must be set}", wantErr: true},
+		{name: "colonQuestionSet", value: "${EXPAND_SET:?must be set}", expected: "set"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configFileYaml, err := ioutil.TempFile("", "TestExpandEnvVarSyntax*.yml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				configFileYaml.Close()
+				os.RemoveAll(configFileYaml.Name())
+			}()
+
+			configFileYaml.WriteString(fmt.Sprintf("nested:\n    key:\n        a: \"%s\"\n", test.value))
+
+			configLoader, err := configuro.NewConfig(
+				configuro.WithoutLoadFromEnvVars(),
+				configuro.WithoutLoadDotEnv(),
+				configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+				configuro.WithoutEnvConfigPathOverload(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			example := &Example{}
+			err = configLoader.Load(example)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && example.Nested.Key.A != test.expected {
+				t.Fatalf("Expanded value = %q, expected: %q", example.Nested.Key.A, test.expected)
+			}
+		})
+	}
+}
+
+func TestExpandConfigReferences(t *testing.T) {
+	configFileYaml, err := ioutil.TempFile("", "TestExpandConfigReferences*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	configFileYaml.WriteString(`
+nested:
+    number: 8080
+    key:
+        a: localhost
+        b: http://${.nested.key.a}:${config:nested.number}
+        c: ${.nested.key.b}/api
+        d: ${.nested.key.missing|${REF_ENV_UNSET|fallback}}
+    key-b:
+        a: ${.nested.key-b.b}
+        b: ${.nested.key-b.a}
+    `)
+
+	_ = os.Setenv("REFERENCES_NESTED_KEY_A", "example.com")
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("REFERENCES"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := &Key{}
+	example := &struct {
+		Nested struct {
+			Key Key
+		}
+	}{}
+	err = configLoader.Load(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// References resolve against the merged config, including values overridden by Environment Variables.
+	expected := Key{A: "example.com", B: "http://example.com:8080", C: "http://example.com:8080/api", D: "fallback"}
+	if example.Nested.Key != expected {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example.Nested.Key, expected)
+	}
+
+	err = configLoader.LoadKey("nested.key-b", key)
+	if err == nil {
+		t.Fatal("Load should fail on cyclic references")
+	}
+	if !strings.Contains(err.Error(), "nested.key-b.a -> nested.key-b.b -> nested.key-b.a") {
+		t.Fatalf("cyclic reference error should name the reference chain, got: %v", err)
+	}
+}
+
+func TestExpandConfigTree(t *testing.T) {
+	configFileYaml, err := ioutil.TempFile("", "TestExpandConfigTree*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	configFileYaml.WriteString(`
+port: ${TREE_PORT}
+template: Hello ${NAME}
+endpoints:
+    ${TREE_REGION}_api: ${TREE_PORT}
+hosts:
+    - addr: ${TREE_HOST}
+    `)
+
+	_ = os.Setenv("TREE_PORT", "8080")
+	_ = os.Setenv("TREE_REGION", "eu")
+	_ = os.Setenv("TREE_HOST", "example.com")
+	_ = os.Setenv("TREE_OBJECT", `{"a": "${TREE_HOST}"}`)
+
+	type Host struct {
+		Addr string
+	}
+
+	type Obj struct {
+		Port      int
+		Template  string `expand:"false"`
+		Endpoints map[string]int
+		Hosts     []Host
+		Object    map[string]string
+	}
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithLoadFromEnvVars("TREE"),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithExpandKeys(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &Obj{}
+	err = configLoader.Load(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Obj{
+		Port:      8080,
+		Template:  "Hello ${NAME}",
+		Endpoints: map[string]int{"eu_api": 8080},
+		Hosts:     []Host{{Addr: "example.com"}},
+		Object:    map[string]string{"a": "example.com"},
+	}
+	if !reflect.DeepEqual(*obj, expected) {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", obj, expected)
+	}
+}
+
+func TestTemplateRendering(t *testing.T) {
+	configFileYaml, err := ioutil.TempFile("", "TestTemplateRendering*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretFile, err := ioutil.TempFile("", "TestTemplateRendering*.secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		secretFile.Close()
+		os.RemoveAll(configFileYaml.Name())
+		os.RemoveAll(secretFile.Name())
+	}()
+
+	secretFile.WriteString("secret")
+	_ = os.Setenv("TEMPLATE_REGION", "eu")
+	_ = os.Setenv("TEMPLATE_ENCODED", "ZGVjb2RlZA==")
+
+	configFileYaml.WriteString(`
+nested:
+    key:
+{{- if eq (env "TEMPLATE_REGION") "eu" }}
+        a: eu.example.com
+{{- else }}
+        a: example.com
+{{- end }}
+        b: {{ env "TEMPLATE_UNSET" | default "defaultB" }}
+        c: {{ file "` + filepath.Base(secretFile.Name()) + `" }}
+        d: {{ b64dec (env "TEMPLATE_ENCODED") }}
+        e: {{ toJSON (env "TEMPLATE_REGION") }}
+    `)
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithTemplateRendering(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	example := &Example{}
+	err = configLoader.Load(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Key{A: "eu.example.com", B: "defaultB", C: "secret", D: "decoded", E: "eu"}
+	if example.Nested.Key != expected {
+		t.Fatalf("Loaded Values doesn't equal expected values. loaded: %v, expected: %v", example.Nested.Key, expected)
+	}
+}
+
+func TestTemplateRenderingError(t *testing.T) {
+	configFileYaml, err := ioutil.TempFile("", "TestTemplateRenderingError*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		configFileYaml.Close()
+		os.RemoveAll(configFileYaml.Name())
+	}()
+
+	configFileYaml.WriteString(`nested:
+    key:
+        a: {{ env "TEMPLATE_UNSET" | required "TEMPLATE_UNSET is required" }}
+`)
+
+	configLoader, err := configuro.NewConfig(
+		configuro.WithoutLoadFromEnvVars(),
+		configuro.WithoutLoadDotEnv(),
+		configuro.WithLoadFromConfigFile(configFileYaml.Name(), true),
+		configuro.WithoutEnvConfigPathOverload(),
+		configuro.WithTemplateRendering(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = configLoader.Load(&Example{})
+	if err == nil {
+		t.Fatal("Load should fail if a required template value is missing")
+	}
+	if !strings.Contains(err.Error(), configFileYaml.Name()+":3") || !strings.Contains(err.Error(), "TEMPLATE_UNSET is required") {
+		t.Fatalf("template error should contain file and line, got: %v", err)
+	}
+}
+
 func TestChangeTagName(t *testing.T) {
 	configFileYaml, err := ioutil.TempFile("", "TestChangeTagName*.yml")
 	if err != nil {
@@ -0,0 +1,137 @@
+package configuro
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTag     = "default"
+	descriptionTag = "description"
+	secretTag      = "secret"
+)
+
+// setDefaults set the default values declared by `default:"value"` tags of configStruct (loaded at key),
+// defaults have the lowest precedence of all sources.
+// Defaults of fields inside pointers to structs, lists, and maps are applied to the merged config by setNestedDefaults.
+func (c *Config) setDefaults(key string, configStruct interface{}) {
+	var path []string
+	if key != "" {
+		path = strings.Split(strings.ToLower(key), c.keyDelimiter)
+	}
+
+	walkStructFields(reflect.TypeOf(configStruct), c.tag, path, func(path []string, field reflect.StructField) bool {
+		if defaultValue, ok := field.Tag.Lookup(defaultTag); ok {
+			fieldKey := strings.Join(path, c.keyDelimiter)
+			c.viper.SetDefault(fieldKey, defaultValue)
+			c.recordSource(fieldKey, Source{Kind: SourceDefault})
+		}
+		return field.Type.Kind() != reflect.Ptr
+	})
+}
+
+// setNestedDefaults set the defaults of fields inside pointers to structs and list and map elements in a merged config tree
+// (decoded into type t at path), and return the tree. A pointer's defaults are set only if its key is set, so unset pointers
+// are left nil. JSON encoded lists and maps (e.g values of Environment Variables) are decoded to set their elements' defaults.
+// nested is whether value is inside a pointer or an element, where defaults are not set by setDefaults.
+func (c *Config) setNestedDefaults(value interface{}, t reflect.Type, path string, nested bool) interface{} {
+	t = underlyingType(t)
+
+	if raw, ok := value.(string); ok {
+		var decoded interface{}
+		if json.Unmarshal([]byte(raw), &decoded) == nil {
+			switch decoded.(type) {
+			case map[string]interface{}:
+				if t.Kind() == reflect.Struct || t.Kind() == reflect.Map {
+					value = decoded
+				}
+			case []interface{}:
+				if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+					value = decoded
+				}
+			}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		walkStructFields(t, c.tag, nil, func(fieldPath []string, field reflect.StructField) bool {
+			key := strings.ToLower(fieldPath[0])
+			fieldKey := c.joinLowerKey(path, key)
+			elem, found := m[key]
+
+			if !found && nested {
+				if defaultValue, ok := field.Tag.Lookup(defaultTag); ok {
+					m[key] = defaultValue
+					c.recordDefaultSource(fieldKey)
+					return false
+				}
+				if field.Type.Kind() != reflect.Ptr && underlyingType(field.Type).Kind() == reflect.Struct {
+					nestedMap := make(map[string]interface{})
+					c.setNestedDefaults(nestedMap, field.Type, fieldKey, true)
+					if len(nestedMap) > 0 {
+						m[key] = nestedMap
+					}
+				}
+				return false
+			}
+
+			if found {
+				m[key] = c.setNestedDefaults(elem, field.Type, fieldKey, nested || field.Type.Kind() == reflect.Ptr)
+			}
+			return false
+		})
+		return m
+	case reflect.Slice, reflect.Array:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		for i, elem := range list {
+			list[i] = c.setNestedDefaults(elem, t.Elem(), c.joinLowerKey(path, strconv.Itoa(i)), true)
+		}
+		return list
+	case reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		for key, elem := range m {
+			m[key] = c.setNestedDefaults(elem, t.Elem(), c.joinLowerKey(path, key), true)
+		}
+		return m
+	}
+	return value
+}
+
+// recordDefaultSource record a default as the source of key, and of the keys holding it that have no source.
+func (c *Config) recordDefaultSource(key string) {
+	source := Source{Kind: SourceDefault}
+	c.sources[key] = source
+	for i := strings.LastIndex(key, c.keyDelimiter); i > 0; i = strings.LastIndex(key, c.keyDelimiter) {
+		key = key[:i]
+		if _, found := c.parentSources[key]; !found {
+			c.parentSources[key] = source
+		}
+	}
+}
+
+// isSecretField return whether a field is tagged with `secret:"true"`.
+func isSecretField(field reflect.StructField) bool {
+	return field.Tag.Get(secretTag) == "true"
+}
+
+// defaultConfig return a Config with the default options without initializing it (e.g loading .env), used by generators.
+func defaultConfig() *Config {
+	c := &Config{}
+	for _, opt := range DefaultOptions() {
+		_ = opt(c)
+	}
+	return c
+}
@@ -0,0 +1,273 @@
+package configuro
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//ChangeType The type of a change of a config key.
+type ChangeType int
+
+const (
+	//ChangeAdded The key was not set in the old config.
+	ChangeAdded ChangeType = iota + 1
+	//ChangeRemoved The key is not set in the new config.
+	ChangeRemoved
+	//ChangeModified The value of the key changed.
+	ChangeModified
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+//Change A change of a config key between two configs.
+type Change struct {
+	// Key of the changed value, named using the config tag (e.g `database.hosts.0.port`).
+	Key string
+	// Type of the change.
+	Type ChangeType
+	// Old value, nil if the key was added. Secrets are masked.
+	Old interface{}
+	// New value, nil if the key was removed. Secrets are masked.
+	New interface{}
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("%s: added %v", c.Key, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s: removed %v", c.Key, c.Old)
+	}
+	return fmt.Sprintf("%s: %v -> %v", c.Key, c.Old, c.New)
+}
+
+//Diff Returns the changes between two loaded configs of the same type using the default `config` tag.
+// See Config.Diff.
+func Diff(old, new interface{}) []Change {
+	return defaultConfig().Diff(old, new)
+}
+
+//Diff Returns the changes between two loaded configs of the same type, in the order of struct fields (and sorted map keys).
+// Structs, maps, lists of structs, pointers, and Value[T] fields are compared recursively, and other values
+// (including lists of values) are compared as a whole. Nil pointers, unset Value[T] fields, missing map keys,
+// and missing list elements are not set, so setting them is an added change and unsetting them is a removed change.
+// Values of fields tagged with `secret:"true"` (and the values nested in them) are masked.
+func (c *Config) Diff(old, new interface{}) []Change {
+	var changes []Change
+	c.diffValues(reflect.ValueOf(old), reflect.ValueOf(new), "", false, &changes)
+	return changes
+}
+
+// diffValues append the changes between old and new at key, invalid values are not set.
+func (c *Config) diffValues(old, new reflect.Value, key string, secret bool, changes *[]Change) {
+	old, new = diffUnwrap(old), diffUnwrap(new)
+	if !old.IsValid() && !new.IsValid() {
+		return
+	}
+
+	if old.IsValid() && new.IsValid() && old.Type() == new.Type() {
+		switch {
+		case old.Kind() == reflect.Struct && isEnvVarsStruct(old.Type()):
+			c.diffStructs(old, new, key, secret, changes)
+			return
+		case old.Kind() == reflect.Map:
+			c.diffMaps(old, new, key, secret, changes)
+			return
+		case (old.Kind() == reflect.Slice || old.Kind() == reflect.Array) && isDiffContainer(old.Type().Elem()):
+			c.diffLists(old, new, key, secret, changes)
+			return
+		}
+
+		if (old.Kind() == reflect.Slice || old.Kind() == reflect.Map) && old.Len() == 0 && new.Len() == 0 {
+			// nil and empty are the same.
+			return
+		}
+		if !reflect.DeepEqual(old.Interface(), new.Interface()) {
+			*changes = append(*changes, Change{Key: key, Type: ChangeModified, Old: diffMask(old, secret), New: diffMask(new, secret)})
+		}
+		return
+	}
+
+	// A value that is set in only one of the configs: compare the values nested in it with nothing, so secrets nested in it are masked.
+	value := old
+	if !value.IsValid() {
+		value = new
+	}
+	if old.IsValid() && new.IsValid() || !isDiffContainer(value.Type()) {
+		change := Change{Key: key, Type: ChangeModified}
+		if old.IsValid() {
+			change.Old = diffMask(old, secret)
+		} else {
+			change.Type = ChangeAdded
+		}
+		if new.IsValid() {
+			change.New = diffMask(new, secret)
+		} else {
+			change.Type = ChangeRemoved
+		}
+		*changes = append(*changes, change)
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		c.diffStructs(old, new, key, secret, changes)
+	case reflect.Map:
+		c.diffMaps(old, new, key, secret, changes)
+	default:
+		c.diffLists(old, new, key, secret, changes)
+	}
+}
+
+func (c *Config) diffStructs(old, new reflect.Value, key string, secret bool, changes *[]Change) {
+	var t reflect.Type
+	if old.IsValid() {
+		t = old.Type()
+	} else {
+		t = new.Type()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported field
+			continue
+		}
+
+		fieldKey, squash := structFieldKey(field, c.tag)
+		if fieldKey == "-" {
+			continue
+		}
+		fieldPath := key
+		if !squash {
+			fieldPath = c.joinDiffKey(key, strings.ToLower(fieldKey))
+		}
+		c.diffValues(diffField(old, i), diffField(new, i), fieldPath, secret || isSecretField(field), changes)
+	}
+}
+
+func (c *Config) diffMaps(old, new reflect.Value, key string, secret bool, changes *[]Change) {
+	keys := make(map[string]reflect.Value)
+	for _, m := range []reflect.Value{old, new} {
+		if !m.IsValid() {
+			continue
+		}
+		for _, k := range m.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var oldElem, newElem reflect.Value
+		if old.IsValid() {
+			oldElem = old.MapIndex(keys[name])
+		}
+		if new.IsValid() {
+			newElem = new.MapIndex(keys[name])
+		}
+		c.diffValues(oldElem, newElem, c.joinDiffKey(key, name), secret, changes)
+	}
+}
+
+func (c *Config) diffLists(old, new reflect.Value, key string, secret bool, changes *[]Change) {
+	length := 0
+	for _, list := range []reflect.Value{old, new} {
+		if list.IsValid() && list.Len() > length {
+			length = list.Len()
+		}
+	}
+
+	for i := 0; i < length; i++ {
+		var oldElem, newElem reflect.Value
+		if old.IsValid() && i < old.Len() {
+			oldElem = old.Index(i)
+		}
+		if new.IsValid() && i < new.Len() {
+			newElem = new.Index(i)
+		}
+		c.diffValues(oldElem, newElem, c.joinDiffKey(key, strconv.Itoa(i)), secret, changes)
+	}
+}
+
+func (c *Config) joinDiffKey(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + c.keyDelimiter + key
+}
+
+// diffUnwrap dereference pointers and interfaces and unwrap Value[T], nil and unset values are invalid.
+func diffUnwrap(v reflect.Value) reflect.Value {
+	for v.IsValid() {
+		switch {
+		case v.Kind() == reflect.Ptr && v.Type().Implements(configValueType):
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			value, isSet := v.Interface().(configValue).get()
+			if !isSet {
+				return reflect.Value{}
+			}
+			v = reflect.ValueOf(value)
+		case reflect.PtrTo(v.Type()).Implements(configValueType):
+			ptr := reflect.New(v.Type())
+			ptr.Elem().Set(v)
+			v = ptr
+		case v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface:
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+func diffField(v reflect.Value, i int) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	return v.Field(i)
+}
+
+// isDiffContainer return whether values of type t are compared by the values nested in them.
+func isDiffContainer(t reflect.Type) bool {
+	t = underlyingType(t)
+	switch t.Kind() {
+	case reflect.Struct:
+		return isEnvVarsStruct(t)
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		return isDiffContainer(t.Elem())
+	}
+	return false
+}
+
+func diffMask(v reflect.Value, secret bool) interface{} {
+	if secret {
+		return secretPlaceholder
+	}
+	return v.Interface()
+}
@@ -0,0 +1,92 @@
+package configuro
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//ReadFile Read a config file into a document the same way it's read when loading: rendered as a template, parsed,
+// its includes resolved, and migrated (if enabled). Keys keep the case used in the file.
+func (c *Config) ReadFile(path string) (map[string]interface{}, error) {
+	doc, err := c.readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.migrations != nil {
+		_, err = c.migrate(doc)
+		if err != nil {
+			return nil, fmt.Errorf("error migrating config file \"%s\": %v", path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+//WriteFile Write a document to a config file atomically, the format is chosen by the file extension (Yaml, Json, or Toml).
+func (c *Config) WriteFile(path string, doc map[string]interface{}) error {
+	return c.writeConfigFile(path, doc)
+}
+
+//ValidateDocument Validate a document against the JSON Schema set by WithSchema, it returns nil if no schema is set.
+func (c *Config) ValidateDocument(doc map[string]interface{}) error {
+	if c.schema == nil {
+		return nil
+	}
+	return c.validateSchema(doc, "")
+}
+
+//MarshalDocument Encode a document in format (yaml, json, or toml).
+func MarshalDocument(doc map[string]interface{}, format string) ([]byte, error) {
+	return encodeConfig("."+format, doc)
+}
+
+//MergeDocuments Deep merge documents into a new document the same way config files are merged when loading,
+// values of later documents take precedence, maps are merged, and other values (including lists) are replaced.
+// Keys are matched case insensitively, and the case used in the document with the highest precedence is kept.
+// Keys of the same document that differ only in case are merged in sorted order (e.g `port` takes precedence over `Port`).
+func MergeDocuments(docs ...map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, doc := range docs {
+		mergeTreeFold(merged, doc)
+	}
+	return merged
+}
+
+// mergeTreeFold deep merge src into dst matching keys case insensitively, values in src take precedence.
+func mergeTreeFold(dst, src map[string]interface{}) {
+	keys := make([]string, 0, len(src))
+	for key := range src {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		srcValue := src[key]
+		dstKey := key
+		for k := range dst {
+			if strings.EqualFold(k, key) {
+				dstKey = k
+				break
+			}
+		}
+		dstValue, found := dst[dstKey]
+		delete(dst, dstKey)
+
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dstValue.(map[string]interface{})
+		if found && srcIsMap && dstIsMap {
+			mergeTreeFold(dstMap, srcMap)
+			dst[key] = dstMap
+			continue
+		}
+		if srcIsMap {
+			// Copy so merging later documents doesn't modify src.
+			copied := make(map[string]interface{}, len(srcMap))
+			mergeTreeFold(copied, srcMap)
+			srcValue = copied
+		}
+		dst[key] = srcValue
+	}
+}
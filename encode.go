@@ -0,0 +1,96 @@
+package configuro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+)
+
+// encodeConfig encode a config tree according to the extension of the file it will be written to.
+func encodeConfig(ext string, doc map[string]interface{}) ([]byte, error) {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		return yaml.Marshal(doc)
+	case ".json", ".jsonc", ".json5":
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case ".toml":
+		tree, err := toml.TreeFromMap(tomlIntegers(doc).(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		data, err := tree.ToTomlString()
+		if err != nil {
+			return nil, err
+		}
+		return []byte(data), nil
+	}
+	return nil, fmt.Errorf("writing config files with extension %s is not supported", ext)
+}
+
+// tomlIntegers copy a config tree with whole floats turned into integers, JSON numbers are decoded as floats
+// and TOML, unlike YAML and JSON, would write them with a fraction (e.g `port = 8080.0`).
+func tomlIntegers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[key] = tomlIntegers(elem)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			list[i] = tomlIntegers(elem)
+		}
+		return list
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	}
+	return value
+}
+
+// writeFileAtomic write data to a temp file in the same directory then rename it to path,
+// so readers never see a partially written file. The permissions of an existing file are kept.
+func writeFileAtomic(path string, data []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
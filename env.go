@@ -0,0 +1,215 @@
+package configuro
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const envTag = "env"
+
+// bindEnvTags set the value of fields tagged with `env:"NAME,ALIAS"` from the first set Environment Variable of the listed names.
+// Explicitly named Environment Variables take precedence over prefixed ones.
+func (c *Config) bindEnvTags(key string, configStruct interface{}) {
+	var path []string
+	if key != "" {
+		path = strings.Split(strings.ToLower(key), c.keyDelimiter)
+	}
+
+	walkStructFields(reflect.TypeOf(configStruct), c.tag, path, func(path []string, field reflect.StructField) bool {
+		names := envTagNames(field)
+		for _, name := range names {
+			if value, isSet := os.LookupEnv(name); isSet {
+				c.viper.Set(strings.Join(path, c.keyDelimiter), value)
+				c.recordSource(strings.Join(path, c.keyDelimiter), Source{Kind: SourceEnv, Name: name})
+				break
+			}
+		}
+		return true
+	})
+}
+
+// envTagNames return the Environment Variables names declared in the field's `env` tag.
+func envTagNames(field reflect.StructField) []string {
+	tagValue := field.Tag.Get(envTag)
+	if tagValue == "" || tagValue == "-" {
+		return nil
+	}
+
+	names := make([]string, 0)
+	for _, name := range strings.Split(tagValue, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// indexedEnv is a prefixed Environment Variable that set an element of a slice (e.g `CONFIG_DATABASE_HOSTS_0_ADDR`).
+type indexedEnv struct {
+	name      string
+	sliceKey  []string
+	sliceType reflect.Type
+	path      []string
+	value     string
+}
+
+// newIndexedEnv return the indexedEnv of an Env Var (`NAME=value`) whose unescaped key index a slice field of configStruct loaded at keyPath.
+func (c *Config) newIndexedEnv(env string, key string, keyPath []string, configStruct interface{}) (indexedEnv, bool) {
+	path := strings.Split(strings.ToLower(key), ".")
+	if len(path) <= len(keyPath) {
+		return indexedEnv{}, false
+	}
+	for i := range keyPath {
+		if path[i] != keyPath[i] {
+			return indexedEnv{}, false
+		}
+	}
+
+	n, sliceType := indexedEnvPath(reflect.TypeOf(configStruct), c.tag, path[len(keyPath):])
+	if n < 0 {
+		return indexedEnv{}, false
+	}
+
+	nameValue := strings.SplitN(env, "=", 2)
+	return indexedEnv{
+		name:      nameValue[0],
+		sliceKey:  path[:len(keyPath)+n],
+		sliceType: sliceType,
+		path:      path[len(keyPath)+n:],
+		value:     nameValue[1],
+	}, true
+}
+
+// indexedEnvPath return how many keys of path lead to a slice field of t when the next key is an index, and the slice type.
+// It return -1 if path doesn't index a slice.
+func indexedEnvPath(t reflect.Type, tagName string, path []string) (int, reflect.Type) {
+	for i, key := range path {
+		t = underlyingType(t)
+		switch t.Kind() {
+		case reflect.Struct:
+			field, found := structFieldByKey(t, key, tagName)
+			if !found {
+				return -1, nil
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Slice, reflect.Array:
+			if _, isIndex := envKeyIndex(key); !isIndex || i == 0 {
+				return -1, nil
+			}
+			return i, t
+		default:
+			return -1, nil
+		}
+	}
+	return -1, nil
+}
+
+// envKeyIndex parse a key as a slice index.
+func envKeyIndex(key string) (int, bool) {
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 || key[0] == '+' {
+		return 0, false
+	}
+	return index, true
+}
+
+// setIndexedEnvs merge indexed Environment Variables into the slices they index.
+// The slice loaded from config files (or a JSON encoded Environment Variable) is kept, indexed values override
+// the elements (or fields of elements) they set, indices past the end extend the slice, and skipped elements are left empty.
+func (c *Config) setIndexedEnvs(envs []indexedEnv) error {
+	sort.Slice(envs, func(i, j int) bool { return envs[i].name < envs[j].name })
+
+	slices := make(map[string]interface{})
+	for _, env := range envs {
+		sliceKey := strings.Join(env.sliceKey, c.keyDelimiter)
+		slice, ok := slices[sliceKey]
+		if !ok {
+			slice = c.viper.Get(sliceKey)
+		}
+
+		c.recordSource(strings.Join(append(env.sliceKey, env.path...), c.keyDelimiter), Source{Kind: SourceEnv, Name: env.name})
+
+		var err error
+		slices[sliceKey], err = setIndexedValue(slice, env.sliceType, c.tag, env.path, env.value)
+		if err != nil {
+			return fmt.Errorf("error setting \"%s\" from environment variable %s: %v", sliceKey, env.name, err)
+		}
+	}
+
+	for sliceKey, slice := range slices {
+		c.viper.Set(sliceKey, slice)
+	}
+	return nil
+}
+
+// setIndexedValue return a copy of container (a list or a map) with value set at path, t is the type container will be decoded into.
+// A JSON encoded container (e.g from an Environment Variable) is decoded first.
+func setIndexedValue(container interface{}, t reflect.Type, tagName string, path []string, value string) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	if t != nil {
+		t = underlyingType(t)
+	}
+
+	if raw, isString := container.(string); isString && raw != "" {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+			return nil, fmt.Errorf("couldn't merge into non JSON value: %v", err)
+		}
+		container = decoded
+	}
+
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		index, isIndex := envKeyIndex(path[0])
+		if isIndex {
+			var list []interface{}
+			if existing, ok := container.([]interface{}); ok {
+				list = append(list, existing...)
+			}
+			for len(list) <= index {
+				list = append(list, nil)
+			}
+
+			elem, err := setIndexedValue(list[index], t.Elem(), tagName, path[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			list[index] = elem
+			return list, nil
+		}
+	}
+
+	var elemType reflect.Type
+	if t != nil {
+		switch t.Kind() {
+		case reflect.Struct:
+			if field, found := structFieldByKey(t, path[0], tagName); found {
+				elemType = field.Type
+			}
+		case reflect.Map:
+			elemType = t.Elem()
+		}
+	}
+
+	m := make(map[string]interface{})
+	if existing, ok := container.(map[string]interface{}); ok {
+		for key, elem := range existing {
+			m[key] = elem
+		}
+	}
+
+	elem, err := setIndexedValue(m[path[0]], elemType, tagName, path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	m[path[0]] = elem
+	return m, nil
+}
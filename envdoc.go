@@ -0,0 +1,205 @@
+package configuro
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	envIndexPlaceholder = "<N>"
+	envKeyPlaceholder   = "<KEY>"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+//EnvVar Documentation of an Environment Variable that sets a config key.
+type EnvVar struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Key         string   `json:"key"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default,omitempty"`
+	Required    bool     `json:"required"`
+	Secret      bool     `json:"secret"`
+	Description string   `json:"description,omitempty"`
+}
+
+//EnvVars Returns the Environment Variables that set every key of configStruct using the default options (`CONFIG_` prefix).
+// See Config.EnvVars.
+func EnvVars(configStruct interface{}) []EnvVar {
+	return defaultConfig().EnvVars(configStruct)
+}
+
+//EnvVarsDoc Generate the documentation of the Environment Variables of configStruct using the default options (`CONFIG_` prefix).
+// See Config.EnvVarsDoc.
+func EnvVarsDoc(configStruct interface{}, format string) ([]byte, error) {
+	return defaultConfig().EnvVarsDoc(configStruct, format)
+}
+
+//EnvVars Returns the Environment Variables that set every key of configStruct, named using the Env prefix and key delimiter.
+// Elements of lists and maps of structs are named with <N> and <KEY> placeholders (e.g `CONFIG_HOSTS_<N>_ADDR`),
+// and names from `env` tags are listed as aliases. A field is required if it has the `required` validation rule.
+func (c *Config) EnvVars(configStruct interface{}) []EnvVar {
+	var envVars []EnvVar
+	c.collectEnvVars(reflect.TypeOf(configStruct), nil, nil, &envVars)
+	return envVars
+}
+
+//EnvVarsDoc Generate the documentation of the Environment Variables of configStruct in format (markdown, text, or json).
+func (c *Config) EnvVarsDoc(configStruct interface{}, format string) ([]byte, error) {
+	envVars := c.EnvVars(configStruct)
+
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return envVarsMarkdown(envVars), nil
+	case "text", "txt":
+		return envVarsText(envVars), nil
+	case "json":
+		if envVars == nil {
+			envVars = []EnvVar{}
+		}
+		var out bytes.Buffer
+		encoder := json.NewEncoder(&out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(envVars)
+		if err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	}
+	return nil, fmt.Errorf("env vars doc format %s is not supported", format)
+}
+
+//EnvVarName Returns the name of the Environment Variable that sets key, using the Env prefix and key delimiter (e.g `CONFIG_DATABASE_HOST`).
+func (c *Config) EnvVarName(key string) string {
+	name := strings.NewReplacer("_", "__", c.keyDelimiter, "_").Replace(strings.ToUpper(key))
+	if c.envPrefix == "" {
+		return name
+	}
+	return c.envPrefix + "_" + name
+}
+
+// collectEnvVars append the Environment Variables of the fields of struct type t at path, parents are the struct types
+// being collected to stop at recursive types.
+func (c *Config) collectEnvVars(t reflect.Type, path []string, parents []reflect.Type, envVars *[]EnvVar) {
+	t = underlyingType(t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for _, parent := range parents {
+		if parent == t {
+			return
+		}
+	}
+	parents = append(parents[:len(parents):len(parents)], t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported field
+			continue
+		}
+
+		fieldKey, squash := structFieldKey(field, c.tag)
+		if fieldKey == "-" {
+			continue
+		}
+		if squash {
+			c.collectEnvVars(field.Type, path, parents, envVars)
+			continue
+		}
+
+		fieldPath := append(append([]string(nil), path...), fieldKey)
+		fieldType := underlyingType(field.Type)
+		switch {
+		case isEnvVarsStruct(fieldType):
+			c.collectEnvVars(fieldType, fieldPath, parents, envVars)
+			continue
+		case (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) && isEnvVarsStruct(underlyingType(fieldType.Elem())):
+			c.collectEnvVars(fieldType.Elem(), append(fieldPath, envIndexPlaceholder), parents, envVars)
+			continue
+		case fieldType.Kind() == reflect.Map && isEnvVarsStruct(underlyingType(fieldType.Elem())):
+			c.collectEnvVars(fieldType.Elem(), append(fieldPath, envKeyPlaceholder), parents, envVars)
+			continue
+		}
+
+		key := strings.Join(fieldPath, c.keyDelimiter)
+		envVar := EnvVar{
+			Name:        c.EnvVarName(key),
+			Aliases:     envTagNames(field),
+			Key:         key,
+			Type:        fieldType.String(),
+			Required:    hasValidationRule(field.Tag.Get(c.validateTag), "required"),
+			Secret:      isSecretField(field),
+			Description: field.Tag.Get(descriptionTag),
+		}
+		if !envVar.Secret {
+			envVar.Default = field.Tag.Get(defaultTag)
+		}
+		*envVars = append(*envVars, envVar)
+	}
+}
+
+// isEnvVarsStruct return whether t is a struct whose fields are documented separately, not a value like time.Time.
+func isEnvVarsStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+func hasValidationRule(rules string, rule string) bool {
+	for _, r := range strings.Split(rules, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func envVarNames(envVar EnvVar) []string {
+	return append([]string{envVar.Name}, envVar.Aliases...)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func envVarsMarkdown(envVars []EnvVar) []byte {
+	var out bytes.Buffer
+	out.WriteString("| Name | Type | Default | Required | Secret | Description |\n")
+	out.WriteString("|------|------|---------|----------|--------|-------------|\n")
+
+	escape := strings.NewReplacer("|", "\\|", "\n", " ").Replace
+	for _, envVar := range envVars {
+		names := envVarNames(envVar)
+		for i, name := range names {
+			names[i] = "`" + name + "`"
+		}
+		defaultValue := ""
+		if envVar.Default != "" {
+			defaultValue = "`" + escape(envVar.Default) + "`"
+		}
+		fmt.Fprintf(&out, "| %s | `%s` | %s | %s | %s | %s |\n",
+			strings.Join(names, ", "), envVar.Type, defaultValue, yesNo(envVar.Required), yesNo(envVar.Secret), escape(envVar.Description))
+	}
+	return out.Bytes()
+}
+
+func envVarsText(envVars []EnvVar) []byte {
+	var out bytes.Buffer
+	w := tabwriter.NewWriter(&out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tDEFAULT\tREQUIRED\tSECRET\tDESCRIPTION")
+	for _, envVar := range envVars {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			strings.Join(envVarNames(envVar), ", "), envVar.Type, envVar.Default, yesNo(envVar.Required), yesNo(envVar.Secret), envVar.Description)
+	}
+	_ = w.Flush()
+	return out.Bytes()
+}
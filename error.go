@@ -26,6 +26,13 @@ type ErrValidationFunc struct {
 	err   error
 }
 
+//ErrValidationSchema Error if the config document doesn't match the JSON Schema set by WithSchema.
+type ErrValidationSchema struct {
+	key     string
+	source  Source
+	message string
+}
+
 func newErrFieldTagValidation(field validator.FieldError, message string) *ErrValidationTag {
 	return &ErrValidationTag{
 		field:   field.Namespace(),
@@ -43,6 +50,27 @@ func newErrValidate(field string, err error) *ErrValidationFunc {
 	}
 }
 
+func (e *ErrValidationSchema) Error() string {
+	key := e.key
+	if key == "" {
+		key = "config"
+	}
+	if e.source.Kind == SourceNone {
+		return fmt.Sprintf(`%s: %s`, key, e.message)
+	}
+	return fmt.Sprintf(`%s (%s): %s`, key, e.source, e.message)
+}
+
+//Key Returns the key path of the invalid value.
+func (e *ErrValidationSchema) Key() string {
+	return e.key
+}
+
+//Source Returns the config source of the invalid value, or SourceNone if the key is missing.
+func (e *ErrValidationSchema) Source() Source {
+	return e.source
+}
+
 func (e *ErrValidationTag) Error() string {
 	return fmt.Sprintf(`%s: %s`, e.field, e.message)
 }
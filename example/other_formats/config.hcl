@@ -0,0 +1,24 @@
+number = 6800
+number_list = "1,2,3,4"
+word = "String set in YAML file"
+another_word = "String set in YAML file (Should be Overriden by ENV)"
+
+word_map {
+  key1 = "value1"
+  key2 = "value2"
+}
+
+database {
+  hosts = [
+    "localhost1:2022",
+    "localhost2:3200",
+    "${DB_HOST_3}:4200",
+  ]
+  username = "${DB_USERNAME|John}"
+  password = 123456
+}
+
+logger {
+  level = "INFO"
+  debug = true
+}
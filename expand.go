@@ -0,0 +1,349 @@
+package configuro
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// envExpander expand ${...} expressions in strings. Supported expressions:
+//	${VAR}            value of VAR, left as is if VAR is not set.
+//	${VAR|default}    default if VAR is not set.
+//	${VAR-default}    default if VAR is not set.
+//	${VAR:-default}   default if VAR is not set or empty.
+//	${VAR?message}    fail with message if VAR is not set.
+//	${VAR:?message}   fail with message if VAR is not set or empty.
+//	${VAR+alternate}  alternate if VAR is set, empty otherwise.
+//	${VAR:+alternate} alternate if VAR is set and not empty, empty otherwise.
+//	$$                a literal $.
+// default, message, and alternate can contain nested expressions (e.g ${A|${B|x}}).
+//
+// If lookupKey is set, expressions can reference other config keys using ${.key.path} or ${config:key.path}
+// with the same operators except the single character ones (-, ?, +) as '-' is allowed in keys.
+type envExpander struct {
+	lookup       func(name string) (string, bool)
+	lookupKey    func(key string) (interface{}, bool)
+	keyDelimiter string
+	resolving    []string
+}
+
+func newEnvExpander() *envExpander {
+	return &envExpander{lookup: os.LookupEnv}
+}
+
+func (c *Config) newExpander() *envExpander {
+	expander := newEnvExpander()
+	expander.keyDelimiter = c.keyDelimiter
+	expander.lookupKey = func(key string) (interface{}, bool) {
+		if c.viper == nil || !c.viper.IsSet(key) {
+			return nil, false
+		}
+		return c.viper.Get(key), true
+	}
+	return expander
+}
+
+//ErrExpand Error if expanding an expression failed (e.g ${VAR:?message} where VAR is not set).
+type ErrExpand struct {
+	name    string
+	message string
+}
+
+func (e *ErrExpand) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("%s: parameter not set or empty", e.name)
+	}
+	return fmt.Sprintf("%s: %s", e.name, e.message)
+}
+
+//ErrCyclicReference Error if config keys reference each other in a cycle.
+type ErrCyclicReference struct {
+	chain []string
+}
+
+func (e *ErrCyclicReference) Error() string {
+	return fmt.Sprintf("cyclic config reference: %s", strings.Join(e.chain, " -> "))
+}
+
+func (e *envExpander) expand(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "$$"):
+			out.WriteByte('$')
+			i += 2
+		case strings.HasPrefix(s[i:], "${"):
+			end := matchingBrace(s, i+2)
+			if end < 0 {
+				// Unterminated expression, leave the rest as is.
+				out.WriteString(s[i:])
+				return out.String(), nil
+			}
+			value, err := e.expandExpression(s[i : end+1])
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(value)
+			i = end + 1
+		default:
+			out.WriteByte(s[i])
+			i++
+		}
+	}
+	return out.String(), nil
+}
+
+// matchingBrace return the index of the '}' closing an expression whose content start at start, or -1.
+func matchingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "$$"):
+			i++
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var expandOperators = []string{":-", ":?", ":+", "|", "-", "?", "+"}
+
+var referenceOperators = []string{":-", ":?", ":+", "|"}
+
+var referencePrefixes = []string{"config:", "."}
+
+// expandExpression expand a single ${...} expression.
+func (e *envExpander) expandExpression(expr string) (string, error) {
+	body := expr[2 : len(expr)-1]
+
+	if e.lookupKey != nil {
+		for _, prefix := range referencePrefixes {
+			if strings.HasPrefix(body, prefix) {
+				return e.expandReference(expr, body[len(prefix):])
+			}
+		}
+	}
+
+	nameEnd := 0
+	for nameEnd < len(body) && isEnvNameChar(body[nameEnd]) {
+		nameEnd++
+	}
+	name := body[:nameEnd]
+	if name == "" {
+		return expr, nil
+	}
+
+	rest := body[nameEnd:]
+	operator := ""
+	for _, op := range expandOperators {
+		if strings.HasPrefix(rest, op) {
+			operator = op
+			break
+		}
+	}
+	if operator == "" && rest != "" {
+		// Not a valid expression, leave it as is.
+		return expr, nil
+	}
+
+	value, isSet := e.lookup(name)
+	return e.apply(expr, name, value, isSet, operator, rest[len(operator):])
+}
+
+// expandReference expand a reference to another config key, body is the expression without the reference prefix.
+func (e *envExpander) expandReference(expr string, body string) (string, error) {
+	keyEnd, operator := len(body), ""
+	for _, op := range referenceOperators {
+		if i := strings.Index(body, op); i >= 0 && i < keyEnd {
+			keyEnd, operator = i, op
+		}
+	}
+	key := body[:keyEnd]
+	if key == "" || strings.Contains(key, "${") {
+		return expr, nil
+	}
+
+	for i, resolving := range e.resolving {
+		if strings.EqualFold(resolving, key) {
+			chain := append(append([]string(nil), e.resolving[i:]...), key)
+			return "", &ErrCyclicReference{chain: chain}
+		}
+	}
+
+	raw, isSet := e.lookupKey(key)
+	value := ""
+	if isSet {
+		e.resolving = append(e.resolving, key)
+		var err error
+		value, err = e.referenceValue(raw)
+		e.resolving = e.resolving[:len(e.resolving)-1]
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return e.apply(expr, key, value, isSet, operator, body[keyEnd+len(operator):])
+}
+
+// referenceValue turn a referenced config value into a string, maps and lists are json encoded.
+func (e *envExpander) referenceValue(raw interface{}) (string, error) {
+	switch value := raw.(type) {
+	case string:
+		return e.expand(value)
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	case nil:
+		return "", nil
+	}
+	return fmt.Sprint(raw), nil
+}
+
+// apply the expression operator given the looked up value.
+func (e *envExpander) apply(expr, name, value string, isSet bool, operator, word string) (string, error) {
+	isEmpty := !isSet || value == ""
+
+	switch operator {
+	case "":
+		if !isSet {
+			return expr, nil
+		}
+		return value, nil
+	case "|", "-":
+		if !isSet {
+			return e.expand(word)
+		}
+	case ":-":
+		if isEmpty {
+			return e.expand(word)
+		}
+	case "?", ":?":
+		if !isSet || (operator == ":?" && isEmpty) {
+			message, err := e.expand(word)
+			if err != nil {
+				return "", err
+			}
+			return "", &ErrExpand{name: name, message: message}
+		}
+	case "+", ":+":
+		if !isSet || (operator == ":+" && isEmpty) {
+			return "", nil
+		}
+		return e.expand(word)
+	}
+
+	return value, nil
+}
+
+func isEnvNameChar(c byte) bool {
+	return c == '_' || c == '@' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// expandTree expand all string values in a config tree, path is the key of the tree.
+// t is the type the tree will be decoded into, it is used to skip fields tagged with `expand:"false"`, a nil t expand everything.
+func (e *envExpander) expandTree(value interface{}, t reflect.Type, tagName string, path string) (interface{}, error) {
+	if t != nil {
+		t = underlyingType(t)
+	}
+
+	switch v := value.(type) {
+	case string:
+		// Track the key being expanded so a reference back to it is reported as a cycle.
+		if path != "" {
+			e.resolving = append(e.resolving, path)
+			defer func() { e.resolving = e.resolving[:len(e.resolving)-1] }()
+		}
+		return e.expand(v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		expanded := make(map[string]interface{}, len(v))
+		for _, key := range keys {
+			elem := v[key]
+			var elemType reflect.Type
+			if t != nil {
+				switch t.Kind() {
+				case reflect.Struct:
+					// Keys that are not decoded or fields tagged with `expand:"false"` are kept as is.
+					field, found := structFieldByKey(t, key, tagName)
+					if !found || field.Tag.Get(expandTag) == "false" {
+						expanded[key] = elem
+						continue
+					}
+					elemType = field.Type
+				case reflect.Map:
+					elemType = t.Elem()
+				}
+			}
+
+			var err error
+			expanded[key], err = e.expandTree(elem, elemType, tagName, e.joinKey(path, key))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return expanded, nil
+	case []interface{}:
+		var elemType reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elemType = t.Elem()
+		}
+		expanded := make([]interface{}, len(v))
+		for i, elem := range v {
+			var err error
+			expanded[i], err = e.expandTree(elem, elemType, tagName, path)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return expanded, nil
+	}
+	return value, nil
+}
+
+func (e *envExpander) joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + e.keyDelimiter + key
+}
+
+// expandKeys expand map keys in a config tree, nested maps only as keys in lists are not config keys.
+func (e *envExpander) expandKeys(m map[string]interface{}) (map[string]interface{}, error) {
+	expanded := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		key, err := e.expand(key)
+		if err != nil {
+			return nil, err
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			value, err = e.expandKeys(nested)
+			if err != nil {
+				return nil, err
+			}
+		}
+		expanded[key] = value
+	}
+	return expanded, nil
+}
@@ -0,0 +1,233 @@
+package configuro
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"github.com/joho/godotenv"
+	"github.com/magiconair/properties"
+	"github.com/pelletier/go-toml"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v2"
+)
+
+func (c *Config) readConfigFile(path string) (map[string]interface{}, error) {
+	return c.readConfigDocument(path, []string{path})
+}
+
+// readConfigDocument read, render, and parse a config file then resolve its includes, stack is the chain of included files.
+func (c *Config) readConfigDocument(path string, stack []string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.configTemplate {
+		data, err = renderTemplate(path, data)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering config file template: %v", err)
+		}
+	}
+
+	doc, err := c.decodeConfig(filepath.Ext(path), data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file \"%s\": %v", path, err)
+	}
+
+	resolved, err := c.resolveIncludes(doc, filepath.Dir(path), stack)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved.(map[string]interface{}), nil
+}
+
+// decodeConfig decode config file content into a map according to its extension.
+// Nested maps are always returned as map[string]interface{} and lists as []interface{}.
+func (c *Config) decodeConfig(ext string, data []byte) (map[string]interface{}, error) {
+	var err error
+	doc := make(map[string]interface{})
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		data, err = yamlIncludeTagsToKeys(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	case ".json":
+		doc, err = decodeJSON(data, c.configJSONComments, false)
+	case ".jsonc":
+		doc, err = decodeJSON(data, true, false)
+	case ".json5":
+		doc, err = decodeJSON(data, true, true)
+	case ".toml":
+		tree, err := toml.LoadBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		doc = tree.ToMap()
+	case ".hcl":
+		if err := hcl.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		return normalizeHCLMap(doc), nil
+	case ".ini":
+		return decodeINI(data, c.keyDelimiter)
+	case ".properties":
+		return decodeProperties(data, c.keyDelimiter)
+	case ".env":
+		return decodeDotEnv(data, c.envPrefix, c.keyDelimiter)
+	default:
+		return nil, fmt.Errorf("file with extension %s is not supported", ext)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeMap(doc), nil
+}
+
+// decodeINI decode INI where each section is a nested key, keys of the default section are top level keys.
+func decodeINI(data []byte, keyDelimiter string) (map[string]interface{}, error) {
+	file, err := ini.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]interface{})
+	for _, section := range file.Sections() {
+		var path []string
+		if section.Name() != ini.DefaultSection {
+			path = strings.Split(section.Name(), keyDelimiter)
+		}
+		for _, key := range section.Keys() {
+			setNestedValue(doc, append(path, key.Name()), key.String())
+		}
+	}
+
+	return doc, nil
+}
+
+// decodeProperties decode Java .properties where keys are split using the key delimiter.
+func decodeProperties(data []byte, keyDelimiter string) (map[string]interface{}, error) {
+	loader := &properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+	props, err := loader.LoadBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]interface{})
+	for _, key := range props.Keys() {
+		value, _ := props.Get(key)
+		setNestedValue(doc, strings.Split(key, keyDelimiter), value)
+	}
+
+	return doc, nil
+}
+
+// decodeDotEnv decode a .env file where keys follow the same naming of environment variables (e.g `CONFIG_NESTED__KEY`).
+// If prefix is set, only keys with the prefix are loaded.
+func decodeDotEnv(data []byte, prefix string, keyDelimiter string) (map[string]interface{}, error) {
+	envMap, err := godotenv.Unmarshal(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]interface{})
+	for envKey, value := range envMap {
+		if prefix != "" {
+			if !strings.HasPrefix(envKey, prefix+"_") {
+				continue
+			}
+			envKey = strings.TrimPrefix(envKey, prefix+"_")
+		}
+		key := strings.ToLower(unescapeEnvKey(envKey, keyDelimiter))
+		setNestedValue(doc, strings.Split(key, keyDelimiter), value)
+	}
+
+	return doc, nil
+}
+
+// setNestedValue set value at path creating nested maps as needed, a non-map value on the path is replaced by a map.
+func setNestedValue(m map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+func normalizeMap(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		m[k] = normalizeValue(v)
+	}
+	return m
+}
+
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return normalizeMap(v)
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalizeValue(e)
+		}
+		return m
+	case []map[string]interface{}:
+		list := make([]interface{}, len(v))
+		for i, e := range v {
+			list[i] = normalizeMap(e)
+		}
+		return list
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeValue(e)
+		}
+		return v
+	}
+	return value
+}
+
+// normalizeHCLMap flatten HCL blocks.
+// HCL decode every block as a list of objects, a single block is flattened to an object so it can map to a nested struct,
+// while repeated blocks are kept as a list so they can map to a slice of structs.
+func normalizeHCLMap(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		m[k] = normalizeHCLValue(v)
+	}
+	return m
+}
+
+func normalizeHCLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return normalizeHCLMap(v)
+	case []map[string]interface{}:
+		if len(v) == 1 {
+			return normalizeHCLMap(v[0])
+		}
+		list := make([]interface{}, len(v))
+		for i, e := range v {
+			list[i] = normalizeHCLMap(e)
+		}
+		return list
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeHCLValue(e)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,187 @@
+package configuro
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	includeKey     = "$include"
+	yamlIncludeTag = "!include"
+)
+
+//ErrIncludeCycle Error if config files include each other in a cycle.
+type ErrIncludeCycle struct {
+	chain []string
+}
+
+func (e *ErrIncludeCycle) Error() string {
+	return fmt.Sprintf("config file include cycle: %s", strings.Join(e.chain, " -> "))
+}
+
+// resolveIncludes replace every `$include` key in doc with the content of the included files (relative to dir).
+// Included files are merged in order into the node holding the `$include` key, then the node's own keys take precedence.
+// stack is the chain of files being included, used to detect cycles.
+func (c *Config) resolveIncludes(value interface{}, dir string, stack []string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		merged := make(map[string]interface{})
+
+		if include, ok := v[includeKey]; ok {
+			patterns, err := includePatterns(include)
+			if err != nil {
+				return nil, err
+			}
+			for _, pattern := range patterns {
+				files, err := globIncludes(pattern, dir)
+				if err != nil {
+					return nil, err
+				}
+				for _, file := range files {
+					doc, err := c.readIncludedFile(file, stack)
+					if err != nil {
+						return nil, err
+					}
+					mergeTree(merged, doc)
+				}
+			}
+		}
+
+		own := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			if key == includeKey {
+				continue
+			}
+			resolved, err := c.resolveIncludes(elem, dir, stack)
+			if err != nil {
+				return nil, err
+			}
+			own[key] = resolved
+		}
+		mergeTree(merged, own)
+
+		return merged, nil
+	case []interface{}:
+		for i, elem := range v {
+			resolved, err := c.resolveIncludes(elem, dir, stack)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+func (c *Config) readIncludedFile(path string, stack []string) (map[string]interface{}, error) {
+	for i, file := range stack {
+		if file == path {
+			chain := append(append([]string(nil), stack[i:]...), path)
+			return nil, &ErrIncludeCycle{chain: chain}
+		}
+	}
+
+	doc, err := c.readConfigDocument(path, append(stack, path))
+	if err != nil {
+		return nil, fmt.Errorf("error including config file \"%s\": %v", path, err)
+	}
+	return doc, nil
+}
+
+func includePatterns(include interface{}) ([]string, error) {
+	switch v := include.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		patterns := make([]string, 0, len(v))
+		for _, elem := range v {
+			pattern, ok := elem.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a path or a list of paths, got: %v", includeKey, include)
+			}
+			patterns = append(patterns, pattern)
+		}
+		return patterns, nil
+	}
+	return nil, fmt.Errorf("%s must be a path or a list of paths, got: %v", includeKey, include)
+}
+
+// globIncludes return the files matching pattern (relative to dir) sorted, a pattern without glob characters must exist.
+func globIncludes(pattern string, dir string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern \"%s\": %v", pattern, err)
+	}
+	if len(files) == 0 && !strings.ContainsAny(pattern, "*?[") {
+		return nil, fmt.Errorf("included config file \"%s\" not found", pattern)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// mergeTree deep merge src into dst, values in src take precedence.
+func mergeTree(dst, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeTree(dstMap, srcMap)
+			continue
+		}
+		dst[key] = srcValue
+	}
+}
+
+// yamlIncludeTagsToKeys rewrite YAML `!include path` tagged values into `{$include: path}` maps.
+func yamlIncludeTagsToKeys(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte(yamlIncludeTag)) {
+		return data, nil
+	}
+
+	var root yamlv3.Node
+	err := yamlv3.Unmarshal(data, &root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rewriteYAMLIncludeTags(&root) {
+		return data, nil
+	}
+
+	return yamlv3.Marshal(&root)
+}
+
+func rewriteYAMLIncludeTags(node *yamlv3.Node) bool {
+	rewritten := false
+	if node.Tag == yamlIncludeTag {
+		value := *node
+		value.Tag = ""
+		if value.Kind == yamlv3.ScalarNode {
+			value.Tag = "!!str"
+		}
+		*node = yamlv3.Node{
+			Kind: yamlv3.MappingNode,
+			Content: []*yamlv3.Node{
+				{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: includeKey},
+				&value,
+			},
+		}
+		return true
+	}
+	for _, child := range node.Content {
+		if rewriteYAMLIncludeTags(child) {
+			rewritten = true
+		}
+	}
+	return rewritten
+}
@@ -0,0 +1,66 @@
+// Package validators implement the string checks shared by the JSON Schema validation of configuro
+// and the validation of loaders generated by configuro-gen.
+package validators
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	hostnameRFC952Regex = regexp.MustCompile(`^[a-zA-Z]([a-zA-Z0-9\-]+[\.]?)*[a-zA-Z0-9]$`)
+	hostnameRegex       = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+	uuidRegex           = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+//Len Returns the number of characters in s.
+func Len(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+//IsURL Returns whether s is an absolute URL.
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != ""
+}
+
+//IsEmail Returns whether s is an email address.
+func IsEmail(s string) bool {
+	address, err := mail.ParseAddress(s)
+	return err == nil && address.Address == s
+}
+
+//IsIP Returns whether s is an IPv4 or IPv6 address.
+func IsIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+//IsIPv4 Returns whether s is an IPv4 address.
+func IsIPv4(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+}
+
+//IsIPv6 Returns whether s is an IPv6 address.
+func IsIPv6(s string) bool {
+	return net.ParseIP(s) != nil && strings.Contains(s, ":")
+}
+
+//IsHostname Returns whether s is a hostname (RFC 1123).
+func IsHostname(s string) bool {
+	return len(s) <= 253 && hostnameRegex.MatchString(s)
+}
+
+//IsHostnameRFC952 Returns whether s is a hostname (RFC 952), as the `hostname` rule of go-playground/validator checks.
+func IsHostnameRFC952(s string) bool {
+	return hostnameRFC952Regex.MatchString(s)
+}
+
+//IsUUID Returns whether s is a UUID.
+func IsUUID(s string) bool {
+	return uuidRegex.MatchString(s)
+}
@@ -0,0 +1,295 @@
+package configuro
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//ErrParse Error if config file content couldn't be parsed, holds the position of the error in the file.
+type ErrParse struct {
+	Line   int
+	Column int
+	err    error
+}
+
+func (e *ErrParse) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.err)
+}
+
+//Unwrap to support errors IS|AS
+func (e *ErrParse) Unwrap() error {
+	return e.err
+}
+
+func newErrParse(data []byte, offset int, err error) *ErrParse {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line, column := 1, 1
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+	return &ErrParse{Line: line, Column: column, err: err}
+}
+
+// decodeJSON decode JSON into a map, errors are reported with line and column.
+// json5 enables JSON5 syntax (comments, trailing commas, unquoted keys, single quoted strings, hex numbers, etc)
+// comments enables comments and trailing commas only (JSONC)
+func decodeJSON(data []byte, comments bool, json5 bool) (map[string]interface{}, error) {
+	source := data
+	var positions []int
+	if comments || json5 {
+		transcoded, pos, err := transcodeJSON(data, json5)
+		if err != nil {
+			return nil, err
+		}
+		data, positions = transcoded, pos
+	}
+
+	doc := make(map[string]interface{})
+	err := json.Unmarshal(data, &doc)
+	if err == nil {
+		return doc, nil
+	}
+
+	// Map the error offset back to the original content.
+	var offset int
+	switch jsonErr := err.(type) {
+	case *json.SyntaxError:
+		offset = int(jsonErr.Offset) - 1
+	case *json.UnmarshalTypeError:
+		offset = int(jsonErr.Offset) - 1
+	default:
+		return nil, err
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if positions != nil {
+		if offset < len(positions) {
+			offset = positions[offset]
+		} else {
+			offset = len(source)
+		}
+	}
+	return nil, newErrParse(source, offset, err)
+}
+
+type jsonTranscoder struct {
+	src       []byte
+	i         int
+	out       []byte
+	positions []int
+	json5     bool
+}
+
+// transcodeJSON convert JSONC/JSON5 into standard JSON, it also return the position in the source of every output byte.
+func transcodeJSON(src []byte, json5 bool) ([]byte, []int, error) {
+	t := &jsonTranscoder{src: src, json5: json5}
+	for t.i < len(t.src) {
+		if err := t.next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return t.out, t.positions, nil
+}
+
+func (t *jsonTranscoder) emit(b byte, pos int) {
+	t.out = append(t.out, b)
+	t.positions = append(t.positions, pos)
+}
+
+func (t *jsonTranscoder) errorf(pos int, format string, args ...interface{}) error {
+	return newErrParse(t.src, pos, fmt.Errorf(format, args...))
+}
+
+func (t *jsonTranscoder) next() error {
+	c := t.src[t.i]
+	switch {
+	case c == '/' && t.i+1 < len(t.src) && t.src[t.i+1] == '/':
+		for t.i < len(t.src) && t.src[t.i] != '\n' {
+			t.i++
+		}
+	case c == '/' && t.i+1 < len(t.src) && t.src[t.i+1] == '*':
+		start := t.i
+		end := strings.Index(string(t.src[t.i+2:]), "*/")
+		if end < 0 {
+			return t.errorf(start, "unterminated comment")
+		}
+		t.i += end + 4
+	case c == ',':
+		if t.isTrailingComma() {
+			t.i++
+			return nil
+		}
+		t.emit(c, t.i)
+		t.i++
+	case c == '"':
+		return t.string('"')
+	case c == '\'' && t.json5:
+		return t.string('\'')
+	case t.json5 && (isIdentStart(c) || c == '+' || c == '.' || (c >= '0' && c <= '9') || c == '-'):
+		return t.literal()
+	default:
+		t.emit(c, t.i)
+		t.i++
+	}
+	return nil
+}
+
+// isTrailingComma check if the comma at the current position follows a value and is followed only by whitespace/comments then a closing bracket.
+func (t *jsonTranscoder) isTrailingComma() bool {
+	if !t.afterValue() {
+		return false
+	}
+	j := t.i + 1
+	for j < len(t.src) {
+		switch {
+		case t.src[j] == ' ' || t.src[j] == '\t' || t.src[j] == '\n' || t.src[j] == '\r':
+			j++
+		case t.src[j] == '/' && j+1 < len(t.src) && t.src[j+1] == '/':
+			for j < len(t.src) && t.src[j] != '\n' {
+				j++
+			}
+		case t.src[j] == '/' && j+1 < len(t.src) && t.src[j+1] == '*':
+			end := strings.Index(string(t.src[j+2:]), "*/")
+			if end < 0 {
+				return false
+			}
+			j += end + 4
+		default:
+			return t.src[j] == '}' || t.src[j] == ']'
+		}
+	}
+	return false
+}
+
+// afterValue check if the last non whitespace output is a value, and not the start of an object/array, a colon, or another comma.
+func (t *jsonTranscoder) afterValue() bool {
+	for j := len(t.out) - 1; j >= 0; j-- {
+		switch t.out[j] {
+		case ' ', '\t', '\n', '\r':
+		case '{', '[', ':', ',':
+			return false
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func (t *jsonTranscoder) string(quote byte) error {
+	start := t.i
+	t.emit('"', t.i)
+	t.i++
+	for t.i < len(t.src) {
+		c := t.src[t.i]
+		switch {
+		case c == quote:
+			t.emit('"', t.i)
+			t.i++
+			return nil
+		case c == '\\' && t.i+1 < len(t.src):
+			escaped := t.src[t.i+1]
+			switch {
+			case t.json5 && escaped == '\n':
+				// JSON5 line continuation.
+			case t.json5 && escaped == '\r':
+				if t.i+2 < len(t.src) && t.src[t.i+2] == '\n' {
+					t.i++
+				}
+			case escaped == '\'':
+				t.emit('\'', t.i)
+			default:
+				t.emit(c, t.i)
+				t.emit(escaped, t.i+1)
+			}
+			t.i += 2
+		case c == '"':
+			// Only reachable in single quoted strings.
+			t.emit('\\', t.i)
+			t.emit('"', t.i)
+			t.i++
+		case c == '\n':
+			return t.errorf(start, "unterminated string")
+		default:
+			t.emit(c, t.i)
+			t.i++
+		}
+	}
+	return t.errorf(start, "unterminated string")
+}
+
+// literal handle JSON5 unquoted keys and number/keyword literals.
+func (t *jsonTranscoder) literal() error {
+	start := t.i
+	for t.i < len(t.src) && isLiteralChar(t.src[t.i]) {
+		t.i++
+	}
+	word := string(t.src[start:t.i])
+
+	switch {
+	case word == "true" || word == "false" || word == "null":
+		t.emitString(word, start)
+	case isIdentStart(word[0]) && word != "Infinity" && word != "NaN":
+		// Unquoted object key.
+		t.emit('"', start)
+		t.emitString(word, start)
+		t.emit('"', t.i-1)
+	default:
+		number, err := json5Number(word)
+		if err != nil {
+			return t.errorf(start, "%s", err)
+		}
+		t.emitString(number, start)
+	}
+	return nil
+}
+
+func (t *jsonTranscoder) emitString(s string, pos int) {
+	for i := 0; i < len(s); i++ {
+		t.emit(s[i], pos+i)
+	}
+}
+
+func json5Number(word string) (string, error) {
+	sign := ""
+	if word[0] == '+' || word[0] == '-' {
+		if word[0] == '-' {
+			sign = "-"
+		}
+		word = word[1:]
+	}
+	if word == "Infinity" || word == "NaN" {
+		return "", fmt.Errorf("%s%s is not supported", sign, word)
+	}
+	if strings.HasPrefix(word, "0x") || strings.HasPrefix(word, "0X") {
+		var n uint64
+		_, err := fmt.Sscanf(word[2:], "%x", &n)
+		if err != nil {
+			return "", fmt.Errorf("invalid hex number %s", word)
+		}
+		return fmt.Sprintf("%s%d", sign, n), nil
+	}
+	if strings.HasPrefix(word, ".") {
+		word = "0" + word
+	}
+	if strings.HasSuffix(word, ".") {
+		word += "0"
+	}
+	return sign + strings.Replace(word, ".e", ".0e", 1), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isLiteralChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9') || c == '.' || c == '+' || c == '-'
+}
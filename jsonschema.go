@@ -0,0 +1,537 @@
+package configuro
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sherifabdlnaby/configuro/internal/validators"
+	"go.uber.org/multierr"
+)
+
+// schemaViolation a value at key that doesn't match a schema.
+type schemaViolation struct {
+	key     string
+	message string
+}
+
+// validateSchema validate the merged config document against the JSON Schema set by WithSchema, key is the key of doc.
+// Property names are matched case insensitively as keys are, and strings are accepted for numbers, booleans, lists, and maps
+// if they can be parsed as such (e.g values of Environment Variables), the same way they're decoded into the config struct.
+func (c *Config) validateSchema(doc interface{}, key string) error {
+	violations := c.validateSchemaValue(c.schema, schemaDocValue(doc), "")
+
+	var errs error
+	for _, violation := range violations {
+		fullKey := violation.key
+		if key != "" {
+			fullKey = c.joinSchemaKey(key, violation.key)
+		}
+		errs = multierr.Append(errs, &ErrValidationSchema{
+			key:     fullKey,
+			source:  c.Source(fullKey),
+			message: violation.message,
+		})
+	}
+
+	// cast errs to ErrValidationErrs if it is multierr (So we use the package error instead of 3rd party error type)
+	if len(multierr.Errors(errs)) > 1 {
+		return ErrValidationErrors{error: errs}
+	}
+	return errs
+}
+
+func (c *Config) joinSchemaKey(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	if key == "" {
+		return path
+	}
+	return path + c.keyDelimiter + key
+}
+
+func (c *Config) validateSchemaValue(schema interface{}, value interface{}, path string) []schemaViolation {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		if allowed, ok := schema.(bool); ok && !allowed {
+			return []schemaViolation{{path, "is not allowed"}}
+		}
+		return nil
+	}
+
+	var violations []schemaViolation
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, schemaViolation{path, fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		resolved, found := resolveSchemaRef(c.schema, ref)
+		if !found {
+			violate("schema reference %s can't be resolved", ref)
+		} else {
+			violations = append(violations, c.validateSchemaValue(resolved, value, path)...)
+		}
+	}
+
+	types := schemaTypes(s["type"])
+	value = coerceSchemaValue(value, types)
+	if len(types) > 0 && !hasSchemaType(types, value) {
+		violate("must be of type %s", strings.Join(types, " or "))
+		return violations
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values := make([]string, len(enum))
+			for i, e := range enum {
+				values[i] = schemaValueString(e)
+			}
+			violate("must be one of: %s", strings.Join(values, ", "))
+		}
+	}
+	if constValue, ok := s["const"]; ok && !reflect.DeepEqual(constValue, value) {
+		violate("must be %s", schemaValueString(constValue))
+	}
+
+	switch v := value.(type) {
+	case float64:
+		violations = append(violations, validateSchemaNumber(s, v, path)...)
+	case string:
+		violations = append(violations, validateSchemaString(s, v, path)...)
+	case []interface{}:
+		violations = append(violations, c.validateSchemaArray(s, v, path)...)
+	case map[string]interface{}:
+		violations = append(violations, c.validateSchemaObject(s, v, path)...)
+	}
+
+	violations = append(violations, c.validateSchemaCombinators(s, value, path)...)
+
+	return violations
+}
+
+func validateSchemaNumber(s map[string]interface{}, v float64, path string) []schemaViolation {
+	var violations []schemaViolation
+	violate := func(format string, n float64) {
+		violations = append(violations, schemaViolation{path, fmt.Sprintf(format, strconv.FormatFloat(n, 'g', -1, 64))})
+	}
+
+	if n, ok := s["minimum"].(float64); ok && v < n {
+		violate("must be >= %s", n)
+	}
+	if n, ok := s["maximum"].(float64); ok && v > n {
+		violate("must be <= %s", n)
+	}
+	if n, ok := s["exclusiveMinimum"].(float64); ok && v <= n {
+		violate("must be > %s", n)
+	}
+	if n, ok := s["exclusiveMaximum"].(float64); ok && v >= n {
+		violate("must be < %s", n)
+	}
+	if n, ok := s["multipleOf"].(float64); ok && n > 0 {
+		if q := v / n; q != math.Trunc(q) {
+			violate("must be a multiple of %s", n)
+		}
+	}
+	return violations
+}
+
+func validateSchemaString(s map[string]interface{}, v string, path string) []schemaViolation {
+	var violations []schemaViolation
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, schemaViolation{path, fmt.Sprintf(format, args...)})
+	}
+
+	length := float64(validators.Len(v))
+	if n, ok := s["minLength"].(float64); ok && length < n {
+		violate("length must be >= %v", n)
+	}
+	if n, ok := s["maxLength"].(float64); ok && length > n {
+		violate("length must be <= %v", n)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+			violate("must match pattern %s", pattern)
+		}
+	}
+	if format, ok := s["format"].(string); ok && !isSchemaFormat(format, v) {
+		violate("must be a valid %s", format)
+	}
+	return violations
+}
+
+func (c *Config) validateSchemaArray(s map[string]interface{}, v []interface{}, path string) []schemaViolation {
+	var violations []schemaViolation
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, schemaViolation{path, fmt.Sprintf(format, args...)})
+	}
+
+	length := float64(len(v))
+	if n, ok := s["minItems"].(float64); ok && length < n {
+		violate("must have at least %v items", n)
+	}
+	if n, ok := s["maxItems"].(float64); ok && length > n {
+		violate("must have at most %v items", n)
+	}
+	if unique, ok := s["uniqueItems"].(bool); ok && unique {
+	uniqueItems:
+		for i := range v {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(v[i], v[j]) {
+					violate("must have unique items")
+					break uniqueItems
+				}
+			}
+		}
+	}
+
+	prefixItems, _ := s["prefixItems"].([]interface{})
+	for i, elem := range v {
+		elemPath := c.joinSchemaKey(path, strconv.Itoa(i))
+		if i < len(prefixItems) {
+			violations = append(violations, c.validateSchemaValue(prefixItems[i], elem, elemPath)...)
+		} else if items, ok := s["items"]; ok {
+			violations = append(violations, c.validateSchemaValue(items, elem, elemPath)...)
+		}
+	}
+	return violations
+}
+
+func (c *Config) validateSchemaObject(s map[string]interface{}, v map[string]interface{}, path string) []schemaViolation {
+	var violations []schemaViolation
+
+	length := float64(len(v))
+	if n, ok := s["minProperties"].(float64); ok && length < n {
+		violations = append(violations, schemaViolation{path, fmt.Sprintf("must have at least %v keys", n)})
+	}
+	if n, ok := s["maxProperties"].(float64); ok && length > n {
+		violations = append(violations, schemaViolation{path, fmt.Sprintf("must have at most %v keys", n)})
+	}
+
+	// Sort keys so errors are reported in the same order.
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, found := lookupFoldKey(v, name); !found {
+				violations = append(violations, schemaViolation{c.joinSchemaKey(path, strings.ToLower(name)), "is required"})
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	patternProperties, _ := s["patternProperties"].(map[string]interface{})
+	additionalProperties, hasAdditionalProperties := s["additionalProperties"]
+	for _, key := range keys {
+		keyPath := c.joinSchemaKey(path, key)
+		matched := false
+
+		if property, found := lookupFoldKey(properties, key); found {
+			matched = true
+			violations = append(violations, c.validateSchemaValue(property, v[key], keyPath)...)
+		}
+		for pattern, property := range patternProperties {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+				matched = true
+				violations = append(violations, c.validateSchemaValue(property, v[key], keyPath)...)
+			}
+		}
+		if !matched && hasAdditionalProperties {
+			violations = append(violations, c.validateSchemaValue(additionalProperties, v[key], keyPath)...)
+		}
+	}
+	return violations
+}
+
+func (c *Config) validateSchemaCombinators(s map[string]interface{}, value interface{}, path string) []schemaViolation {
+	var violations []schemaViolation
+
+	if allOf, ok := s["allOf"].([]interface{}); ok {
+		for _, schema := range allOf {
+			violations = append(violations, c.validateSchemaValue(schema, value, path)...)
+		}
+	}
+
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		if matches, message := c.schemaMatches(anyOf, value, path); matches == 0 {
+			violations = append(violations, schemaViolation{path, message})
+		}
+	}
+
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		matches, message := c.schemaMatches(oneOf, value, path)
+		if matches == 0 {
+			violations = append(violations, schemaViolation{path, message})
+		} else if matches > 1 {
+			violations = append(violations, schemaViolation{path, "must match exactly one of the oneOf schemas"})
+		}
+	}
+
+	if not, ok := s["not"]; ok && len(c.validateSchemaValue(not, value, path)) == 0 {
+		violations = append(violations, schemaViolation{path, "must not match the schema in not"})
+	}
+
+	if ifSchema, ok := s["if"]; ok {
+		if len(c.validateSchemaValue(ifSchema, value, path)) == 0 {
+			if then, ok := s["then"]; ok {
+				violations = append(violations, c.validateSchemaValue(then, value, path)...)
+			}
+		} else if elseSchema, ok := s["else"]; ok {
+			violations = append(violations, c.validateSchemaValue(elseSchema, value, path)...)
+		}
+	}
+
+	return violations
+}
+
+// schemaMatches return the number of schemas value matches, and the error message if it matches none.
+// The message lists the alternatives if every schema fails with a single error on the value itself (e.g anyOf formats).
+func (c *Config) schemaMatches(schemas []interface{}, value interface{}, path string) (int, string) {
+	matches := 0
+	var alternatives []string
+	for _, schema := range schemas {
+		violations := c.validateSchemaValue(schema, value, path)
+		if len(violations) == 0 {
+			matches++
+			continue
+		}
+		if len(violations) == 1 && violations[0].key == path {
+			alternatives = append(alternatives, violations[0].message)
+		}
+	}
+
+	if len(alternatives) == len(schemas) && len(schemas) > 0 {
+		return matches, strings.Join(alternatives, " or ")
+	}
+	return matches, "must match one of the schemas"
+}
+
+// resolveSchemaRef resolve a reference to a schema in the same document (e.g `#/$defs/host`).
+func resolveSchemaRef(root interface{}, ref string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return root, true
+	}
+
+	current := root
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, found := node[token]
+			if !found {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func lookupFoldKey(m map[string]interface{}, key string) (interface{}, bool) {
+	if value, found := m[key]; found {
+		return value, true
+	}
+	for k, value := range m {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func schemaTypes(t interface{}) []string {
+	switch v := t.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var types []string
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func hasSchemaType(types []string, value interface{}) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := value.(float64); ok && n == math.Trunc(n) {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// coerceSchemaValue parse strings into the types required by the schema, so values of Environment Variables match them.
+func coerceSchemaValue(value interface{}, types []string) interface{} {
+	s, ok := value.(string)
+	if !ok || len(types) == 0 || hasSchemaType(types, value) {
+		return value
+	}
+
+	for _, t := range types {
+		switch t {
+		case "integer", "number":
+			if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && hasSchemaType([]string{t}, n) {
+				return n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				return b
+			}
+		case "array", "object":
+			var v interface{}
+			if err := json.Unmarshal([]byte(s), &v); err == nil && hasSchemaType([]string{t}, v) {
+				return v
+			}
+		}
+	}
+	return value
+}
+
+// schemaDocValue convert a config tree to JSON values (nil, bool, float64, string, []interface{}, map[string]interface{}).
+func schemaDocValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, float64, string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = schemaDocValue(rv.Index(i).Interface())
+		}
+		return list
+	case reflect.Map:
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = schemaDocValue(iter.Value().Interface())
+		}
+		return m
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return schemaDocValue(rv.Elem().Interface())
+	}
+	return fmt.Sprint(value)
+}
+
+func schemaValueString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// isSchemaFormat return whether s is valid in format, unknown formats are always valid.
+func isSchemaFormat(format string, s string) bool {
+	switch format {
+	case "email":
+		return validators.IsEmail(s)
+	case "uri":
+		return validators.IsURL(s)
+	case "uri-reference":
+		_, err := url.Parse(s)
+		return err == nil
+	case "ipv4":
+		return validators.IsIPv4(s)
+	case "ipv6":
+		return validators.IsIPv6(s)
+	case "hostname":
+		return validators.IsHostname(s)
+	case "uuid":
+		return validators.IsUUID(s)
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "regex":
+		_, err := regexp.Compile(s)
+		return err == nil
+	}
+	return true
+}
@@ -25,13 +25,148 @@ func (c *Config) LoadKey(key string, configStruct interface{}) error {
 func (c *Config) loadInternal(key string, configStruct interface{}) error {
 	var err error
 
+	// Start from a fresh viper so values from previously loaded files don't linger.
+	c.initViper()
+
+	c.sources = make(map[string]Source)
+	c.parentSources = make(map[string]Source)
+	c.mapKeysCase = nil
+	if c.preserveMapKeysCase {
+		c.mapKeysCase = make(map[string]string)
+	}
+
+	// Defaults declared by `default` tags.
+	c.setDefaults(key, configStruct)
+
 	// Bind Env Vars
+	var indexedEnvs []indexedEnv
 	if c.envLoad {
-		c.bindAllEnvsWithPrefix()
+		indexedEnvs = c.bindAllEnvsWithPrefix(key, configStruct)
 	}
 
 	if c.configFileLoad {
-		err := c.viper.ReadInConfig()
+		err = c.readConfigFiles()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Merge Env Vars indexing slices into the slices loaded from files.
+	if len(indexedEnvs) > 0 {
+		err = c.setIndexedEnvs(indexedEnvs)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Bind Env Vars explicitly named by `env` tags
+	if c.envLoad {
+		c.bindEnvTags(key, configStruct)
+	}
+
+	// Load values set under deprecated keys into the keys replacing them.
+	err = c.applyKeyAliases(c.collectKeyAliases(key, configStruct))
+	if err != nil {
+		return err
+	}
+
+	// Merged config from all sources.
+	var tree interface{} = c.viper.AllSettings()
+	if key != "" {
+		tree = lookupTree(c.viper.AllSettings(), strings.Split(strings.ToLower(key), c.keyDelimiter))
+	}
+
+	if c.preserveMapKeysCase {
+		tree = c.restoreMapKeysCase(tree, reflect.TypeOf(configStruct), strings.ToLower(key))
+	}
+
+	// Defaults of fields inside set pointers and list and map elements.
+	tree = c.setNestedDefaults(tree, reflect.TypeOf(configStruct), strings.ToLower(key), false)
+
+	// Expand the merged config before decoding.
+	if c.configEnvExpand {
+		tree, err = c.newExpander().expandTree(tree, reflect.TypeOf(configStruct), c.tag, strings.ToLower(key))
+		if err != nil {
+			return fmt.Errorf("error expanding config: %v", err)
+		}
+	}
+
+	// Validate the merged document against the JSON Schema.
+	if c.schema != nil {
+		err = c.validateSchema(tree, strings.ToLower(key))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Unmarshalling
+	err = c.decode(tree, configStruct)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling config: %v", err)
+	}
+
+	c.setValueSources(reflect.ValueOf(configStruct), strings.ToLower(key))
+
+	return nil
+}
+
+// decode config tree into output using the same decoder config used by viper.
+func (c *Config) decode(input interface{}, output interface{}) error {
+	decoderConfig := &mapstructure.DecoderConfig{
+		Metadata:         nil,
+		Result:           output,
+		WeaklyTypedInput: true,
+	}
+	c.decodeHook(decoderConfig)
+	setTagName(c.tag)(decoderConfig)
+
+	decoder, err := mapstructure.NewDecoder(decoderConfig)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(input)
+}
+
+// lookupTree return the value at path in a config tree, or nil if not found.
+func lookupTree(tree map[string]interface{}, path []string) interface{} {
+	var value interface{} = tree
+	for _, key := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value, ok = m[key]
+		if !ok {
+			return nil
+		}
+	}
+	return value
+}
+
+func (c *Config) readConfigFiles() error {
+	c.configFilesUsed = nil
+
+	files := []string{c.configFilepath}
+
+	if c.configSearch {
+		var err error
+		files, err = c.findConfigFiles()
+		if err != nil {
+			return fmt.Errorf("error searching for config file: %v", err)
+		}
+
+		if len(files) == 0 {
+			if c.configFileErrIfNotFound {
+				return fmt.Errorf("error config file not found in search paths: %v", c.searchPaths())
+			}
+			return nil
+		}
+	}
+
+	// Merge from the lowest precedence file to the highest.
+	var used []string
+	for i := len(files) - 1; i >= 0; i-- {
+		doc, err := c.readConfigFile(files[i])
 		if err != nil {
 			pathErr, ok := err.(*os.PathError)
 			if !ok {
@@ -41,31 +176,78 @@ func (c *Config) loadInternal(key string, configStruct interface{}) error {
 			if c.configFileErrIfNotFound && pathErr.Op == "open" {
 				return fmt.Errorf("error config file not found. err: %v", err)
 			}
+			continue
 		}
-	}
 
-	// Unmarshalling
-	if key == "" {
-		err = c.viper.Unmarshal(configStruct, c.decodeHook, setTagName(c.tag))
-	} else {
-		err = c.viper.UnmarshalKey(key, configStruct, c.decodeHook, setTagName(c.tag))
-	}
+		if c.migrations != nil {
+			migrated, err := c.migrate(doc)
+			if err != nil {
+				return fmt.Errorf("error migrating config file \"%s\": %v", files[i], err)
+			}
+			if migrated && c.migrationsWriteBack {
+				err = c.writeMigratedFile(files[i], doc)
+				if err != nil {
+					return fmt.Errorf("error writing migrated config file \"%s\": %v", files[i], err)
+				}
+			}
+		}
 
-	if err != nil {
-		return fmt.Errorf("error unmarshalling config: %v", err)
+		if c.configEnvExpand && c.configExpandKeys {
+			doc, err = c.newExpander().expandKeys(doc)
+			if err != nil {
+				return fmt.Errorf("error expanding config keys in \"%s\": %v", files[i], err)
+			}
+		}
+
+		if c.preserveMapKeysCase {
+			c.recordKeysCase(doc, "")
+		}
+		c.recordTreeSources(doc, "", Source{Kind: SourceFile, Name: files[i]})
+
+		err = c.viper.MergeConfigMap(doc)
+		if err != nil {
+			return fmt.Errorf("error reading config data from \"%s\": %v", files[i], err)
+		}
+		used = append([]string{files[i]}, used...)
 	}
 
+	c.configFilesUsed = used
 	return nil
 }
 
-func (c *Config) bindAllEnvsWithPrefix() {
+// bindAllEnvsWithPrefix bind Env Vars with prefix, Env Vars that index a slice field of configStruct (loaded at key)
+// are returned instead so they can be merged with the slice loaded from config files.
+func (c *Config) bindAllEnvsWithPrefix(key string, configStruct interface{}) []indexedEnv {
+	var keyPath []string
+	if key != "" {
+		keyPath = strings.Split(strings.ToLower(key), c.keyDelimiter)
+	}
+
+	var indexed []indexedEnv
 	envKVRegex := regexp.MustCompile("^" + c.envPrefix + "_" + "(.*)=.*$")
 	Envvars := os.Environ()
 	for _, env := range Envvars {
 		match := envKVRegex.FindSubmatch([]byte(env))
 		if match != nil {
-			matchUnescaper := strings.NewReplacer("__", "_", "_", ".")
-			matchUnescaped := matchUnescaper.Replace(string(match[1]))
+			matchUnescaped := unescapeEnvKey(string(match[1]), ".")
+			if c.preserveMapKeysCase {
+				c.recordEnvKeyCase(matchUnescaped, ".")
+			}
+
+			nameValue := strings.SplitN(env, "=", 2)
+			c.recordSource(unescapeEnvKey(string(match[1]), c.keyDelimiter), Source{Kind: SourceEnv, Name: nameValue[0]})
+
+			if indexedEnv, isIndexed := c.newIndexedEnv(env, matchUnescaped, keyPath, configStruct); isIndexed {
+				indexed = append(indexed, indexedEnv)
+				continue
+			}
+
+			// Viper look up Env Vars by their upper case name, so Env Vars with lower case letters are set directly.
+			if nameValue[0] != strings.ToUpper(nameValue[0]) {
+				c.viper.Set(matchUnescaped, nameValue[1])
+				continue
+			}
+
 			err := c.viper.BindEnv(matchUnescaped)
 
 			if err != nil {
@@ -74,6 +256,12 @@ func (c *Config) bindAllEnvsWithPrefix() {
 			}
 		}
 	}
+	return indexed
+}
+
+// unescapeEnvKey turn environment variable name (without prefix) into a config key, `_` separate nested keys and `__` is an escaped `_`.
+func unescapeEnvKey(envKey string, keyDelimiter string) string {
+	return strings.NewReplacer("__", "_", "_", keyDelimiter).Replace(envKey)
 }
 
 func setTagName(hook string) viper.DecoderConfigOption {
@@ -166,36 +354,3 @@ func stringJSONObjToStruct() func(f reflect.Kind, t reflect.Kind, data interface
 		return ret, nil
 	}
 }
-
-func expandEnvVariablesWithDefaults() func(f reflect.Kind, t reflect.Kind, data interface{}) (interface{}, error) {
-	var configWithEnvExpand = regexp.MustCompile(`(\${([\w@.]+)(\|([\w@.:,]+)?)?})`)
-	var exactMatchEnvExpand = regexp.MustCompile(`^` + configWithEnvExpand.String() + `$`)
-	return func(
-		f reflect.Kind,
-		t reflect.Kind,
-		data interface{}) (interface{}, error) {
-		if f != reflect.String {
-			return data, nil
-		}
-
-		raw := data.(string)
-		ret := configWithEnvExpand.ReplaceAllStringFunc(raw, func(s string) string {
-			matches := exactMatchEnvExpand.FindAllStringSubmatch(s, -1)
-			if matches == nil {
-				return s
-			}
-			envKey := matches[0][2]
-			isEnvDefaultSet := matches[0][3] != ""
-			envDefault := matches[0][4]
-			envValue, found := os.LookupEnv(envKey)
-			if !found {
-				if isEnvDefaultSet {
-					return envDefault
-				}
-				return s
-			}
-			return envValue
-		})
-		return ret, nil
-	}
-}
@@ -0,0 +1,349 @@
+//Package loader Runtime of the loaders generated by configuro-gen, it converts config values without reflection.
+// Values are converted the same way configuro decodes them, strings are parsed into numbers, booleans, and durations
+// (e.g values of Environment Variables), and lists and maps can be JSON encoded strings.
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+)
+
+//ReadFile Read a Yaml, Json, or Toml config file into a document according to its extension.
+// Unlike Config.ReadFile, templates and includes are not supported.
+func ReadFile(path string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".toml":
+		var tree *toml.Tree
+		tree, err = toml.LoadBytes(data)
+		if err == nil {
+			doc = tree.ToMap()
+		}
+	default:
+		return nil, fmt.Errorf("file with extension %s is not supported", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file \"%s\": %v", path, err)
+	}
+
+	if doc == nil {
+		return map[string]interface{}{}, nil
+	}
+	return Map(doc)
+}
+
+//Env Returns the value of the first set Environment Variable of names.
+func Env(names ...string) (string, bool) {
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+//FieldEnv Returns the value of the first set Environment Variable of names (from `env` tags), or of the prefixed
+// Environment Variable of the field. As configuro does, the prefixed Environment Variable is ignored if it's empty.
+func FieldEnv(prefixed string, names ...string) (string, bool) {
+	if value, ok := Env(names...); ok {
+		return value, true
+	}
+	if value := os.Getenv(prefixed); value != "" {
+		return value, true
+	}
+	return "", false
+}
+
+//EnvPrefixed Returns whether any Environment Variable starting with prefix is set and not empty.
+func EnvPrefixed(prefix string) bool {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, prefix) && !strings.HasSuffix(env, "=") {
+			return true
+		}
+	}
+	return false
+}
+
+//Lookup Returns the value of key in doc, keys are matched case insensitively.
+func Lookup(doc map[string]interface{}, key string) (interface{}, bool) {
+	if value, found := doc[key]; found {
+		return value, true
+	}
+	for k, value := range doc {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+//Join Returns the key of key nested in path.
+func Join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+//Index Returns the key of the element at index i of the list at path.
+func Index(path string, i int) string {
+	return Join(path, strconv.Itoa(i))
+}
+
+//Map Converts a map or a JSON encoded object to a map with string keys.
+func Map(value interface{}) (map[string]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[fmt.Sprint(key)] = elem
+		}
+		return m, nil
+	case string:
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &m); err != nil {
+			return nil, fmt.Errorf("expected a map, got %q", v)
+		}
+		return m, nil
+	case nil:
+		return map[string]interface{}{}, nil
+	}
+	return nil, fmt.Errorf("expected a map, got %T", value)
+}
+
+//List Converts a list or a JSON encoded array to a list, other values are a list of one element.
+func List(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, nil
+	case []map[string]interface{}:
+		list := make([]interface{}, len(v))
+		for i, elem := range v {
+			list[i] = elem
+		}
+		return list, nil
+	case string:
+		var list []interface{}
+		if err := json.Unmarshal([]byte(v), &list); err == nil {
+			return list, nil
+		}
+		if v == "" {
+			return []interface{}{}, nil
+		}
+		// Comma separated values, as `default` tags of lists.
+		var elems []interface{}
+		for _, elem := range strings.Split(v, ",") {
+			elems = append(elems, elem)
+		}
+		return elems, nil
+	case nil:
+		return []interface{}{}, nil
+	}
+	return []interface{}{value}, nil
+}
+
+//String Converts a value to a string.
+func String(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	return "", fmt.Errorf("expected a string, got %T", value)
+}
+
+//Bool Converts a value to a bool, strings are parsed and numbers are true if they're not zero.
+func Bool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if v == "" {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("expected a bool, got %q", v)
+		}
+		return b, nil
+	case nil:
+		return false, nil
+	}
+	f, err := Float(value, 64)
+	if err != nil {
+		return false, fmt.Errorf("expected a bool, got %T", value)
+	}
+	return f != 0, nil
+}
+
+//Int Converts a value to an int that fits in bitSize bits (0 for int).
+func Int(value interface{}, bitSize int) (int64, error) {
+	var n int64
+	switch v := value.(type) {
+	case int:
+		n = int64(v)
+	case int8:
+		n = int64(v)
+	case int16:
+		n = int64(v)
+	case int32:
+		n = int64(v)
+	case int64:
+		n = v
+	case uint, uint8, uint16, uint32, uint64:
+		u, _ := Uint(v, 64)
+		n = int64(u)
+	case float32, float64:
+		f, _ := Float(v, 64)
+		n = int64(f)
+	case bool:
+		if v {
+			n = 1
+		}
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		parsed, err := strconv.ParseInt(strings.TrimSpace(v), 0, bitSize)
+		if err != nil {
+			return 0, fmt.Errorf("expected an integer, got %q", v)
+		}
+		return parsed, nil
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("expected an integer, got %T", value)
+	}
+	return checkIntRange(n, bitSize)
+}
+
+func checkIntRange(n int64, bitSize int) (int64, error) {
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+	if bitSize < 64 && (n < -1<<(bitSize-1) || n >= 1<<(bitSize-1)) {
+		return 0, fmt.Errorf("%d overflows a %d bits integer", n, bitSize)
+	}
+	return n, nil
+}
+
+//Uint Converts a value to an unsigned int that fits in bitSize bits (0 for uint).
+func Uint(value interface{}, bitSize int) (uint64, error) {
+	switch v := value.(type) {
+	case uint:
+		return uint64(v), nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		parsed, err := strconv.ParseUint(strings.TrimSpace(v), 0, bitSize)
+		if err != nil {
+			return 0, fmt.Errorf("expected an unsigned integer, got %q", v)
+		}
+		return parsed, nil
+	}
+
+	n, err := Int(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected an unsigned integer, got %T", value)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("expected an unsigned integer, got %d", n)
+	}
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+	if bitSize < 64 && uint64(n) >= 1<<bitSize {
+		return 0, fmt.Errorf("%d overflows a %d bits unsigned integer", n, bitSize)
+	}
+	return uint64(n), nil
+}
+
+//Float Converts a value to a float of bitSize bits.
+func Float(value interface{}, bitSize int) (float64, error) {
+	switch v := value.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), bitSize)
+		if err != nil {
+			return 0, fmt.Errorf("expected a number, got %q", v)
+		}
+		return parsed, nil
+	case uint, uint8, uint16, uint32, uint64:
+		u, _ := Uint(v, 64)
+		return float64(u), nil
+	}
+
+	n, err := Int(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number, got %T", value)
+	}
+	return float64(n), nil
+}
+
+//Duration Converts a value to a duration, strings are parsed (e.g `1m30s`) and numbers are nanoseconds.
+func Duration(value interface{}) (time.Duration, error) {
+	if s, ok := value.(string); ok {
+		if s == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return 0, fmt.Errorf("expected a duration, got %q", s)
+		}
+		return d, nil
+	}
+
+	n, err := Int(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a duration, got %T", value)
+	}
+	return time.Duration(n), nil
+}
+
+//EnvName Returns the name of the Environment Variable name nested in prefix (e.g `CONFIG_DATABASE`).
+func EnvName(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
@@ -0,0 +1,305 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		bitSize  int
+		expected int64
+		err      string
+	}{
+		{value: 42, bitSize: 0, expected: 42},
+		{value: int64(-7), bitSize: 64, expected: -7},
+		{value: uint8(200), bitSize: 16, expected: 200},
+		{value: 3.9, bitSize: 0, expected: 3},
+		{value: true, bitSize: 8, expected: 1},
+		{value: " 0x1F ", bitSize: 0, expected: 31},
+		{value: "", bitSize: 0, expected: 0},
+		{value: nil, bitSize: 0, expected: 0},
+		{value: 127, bitSize: 8, expected: 127},
+		{value: -128, bitSize: 8, expected: -128},
+		{value: 128, bitSize: 8, err: "128 overflows a 8 bits integer"},
+		{value: -129, bitSize: 8, err: "-129 overflows a 8 bits integer"},
+		{value: "200", bitSize: 8, err: `expected an integer, got "200"`},
+		{value: "abc", bitSize: 0, err: `expected an integer, got "abc"`},
+		{value: []interface{}{1}, bitSize: 0, err: "expected an integer, got []interface {}"},
+	}
+
+	for _, test := range tests {
+		n, err := Int(test.value, test.bitSize)
+		checkResult(t, "Int", test.value, n, test.expected, err, test.err)
+	}
+}
+
+func TestUint(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		bitSize  int
+		expected uint64
+		err      string
+	}{
+		{value: uint64(1 << 63), bitSize: 64, expected: 1 << 63},
+		{value: 255, bitSize: 8, expected: 255},
+		{value: "65535", bitSize: 16, expected: 65535},
+		{value: "", bitSize: 0, expected: 0},
+		{value: 2.5, bitSize: 0, expected: 2},
+		{value: 256, bitSize: 8, err: "256 overflows a 8 bits unsigned integer"},
+		{value: "65536", bitSize: 16, err: `expected an unsigned integer, got "65536"`},
+		{value: -1, bitSize: 0, err: "expected an unsigned integer, got -1"},
+		{value: map[string]interface{}{}, bitSize: 0, err: "expected an unsigned integer, got map[string]interface {}"},
+	}
+
+	for _, test := range tests {
+		n, err := Uint(test.value, test.bitSize)
+		checkResult(t, "Uint", test.value, n, test.expected, err, test.err)
+	}
+}
+
+func TestFloat(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		bitSize  int
+		expected float64
+		err      string
+	}{
+		{value: 1.5, bitSize: 64, expected: 1.5},
+		{value: float32(0.25), bitSize: 32, expected: 0.25},
+		{value: 3, bitSize: 64, expected: 3},
+		{value: uint16(7), bitSize: 64, expected: 7},
+		{value: " 1e3 ", bitSize: 64, expected: 1000},
+		{value: "", bitSize: 64, expected: 0},
+		{value: "1e40", bitSize: 32, err: `expected a number, got "1e40"`},
+		{value: "abc", bitSize: 64, err: `expected a number, got "abc"`},
+		{value: []interface{}{}, bitSize: 64, err: "expected a number, got []interface {}"},
+	}
+
+	for _, test := range tests {
+		f, err := Float(test.value, test.bitSize)
+		checkResult(t, "Float", test.value, f, test.expected, err, test.err)
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected bool
+		err      string
+	}{
+		{value: true, expected: true},
+		{value: "true", expected: true},
+		{value: "0", expected: false},
+		{value: "", expected: false},
+		{value: nil, expected: false},
+		{value: 2, expected: true},
+		{value: 0.0, expected: false},
+		{value: "yes", err: `expected a bool, got "yes"`},
+		{value: []interface{}{}, err: "expected a bool, got []interface {}"},
+	}
+
+	for _, test := range tests {
+		b, err := Bool(test.value)
+		checkResult(t, "Bool", test.value, b, test.expected, err, test.err)
+	}
+}
+
+func TestString(t *testing.T) {
+	date := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value    interface{}
+		expected string
+		err      string
+	}{
+		{value: "abc", expected: "abc"},
+		{value: nil, expected: ""},
+		{value: 42, expected: "42"},
+		{value: 1.5, expected: "1.5"},
+		{value: false, expected: "false"},
+		{value: date, expected: "2020-01-02T03:04:05Z"},
+		{value: map[string]interface{}{}, err: "expected a string, got map[string]interface {}"},
+	}
+
+	for _, test := range tests {
+		s, err := String(test.value)
+		checkResult(t, "String", test.value, s, test.expected, err, test.err)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected time.Duration
+		err      string
+	}{
+		{value: "1m30s", expected: 90 * time.Second},
+		{value: " 5ms ", expected: 5 * time.Millisecond},
+		{value: "", expected: 0},
+		{value: 1000, expected: time.Microsecond},
+		{value: nil, expected: 0},
+		{value: "5", err: `expected a duration, got "5"`},
+		{value: []interface{}{}, err: "expected a duration, got []interface {}"},
+	}
+
+	for _, test := range tests {
+		d, err := Duration(test.value)
+		checkResult(t, "Duration", test.value, d, test.expected, err, test.err)
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected []interface{}
+		err      string
+	}{
+		{value: []interface{}{1, "a"}, expected: []interface{}{1, "a"}},
+		{value: []map[string]interface{}{{"a": 1}}, expected: []interface{}{map[string]interface{}{"a": 1}}},
+		{value: `[1, "a"]`, expected: []interface{}{1.0, "a"}},
+		{value: "a,b", expected: []interface{}{"a", "b"}},
+		{value: "", expected: []interface{}{}},
+		{value: nil, expected: []interface{}{}},
+		{value: 5, expected: []interface{}{5}},
+	}
+
+	for _, test := range tests {
+		list, err := List(test.value)
+		checkResult(t, "List", test.value, list, test.expected, err, test.err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected map[string]interface{}
+		err      string
+	}{
+		{value: map[string]interface{}{"a": 1}, expected: map[string]interface{}{"a": 1}},
+		{value: map[interface{}]interface{}{"a": 1, 2: "b"}, expected: map[string]interface{}{"a": 1, "2": "b"}},
+		{value: `{"a": 1}`, expected: map[string]interface{}{"a": 1.0}},
+		{value: nil, expected: map[string]interface{}{}},
+		{value: "a=1", err: `expected a map, got "a=1"`},
+		{value: 5, err: "expected a map, got int"},
+	}
+
+	for _, test := range tests {
+		m, err := Map(test.value)
+		checkResult(t, "Map", test.value, m, test.expected, err, test.err)
+	}
+}
+
+// checkResult check the result of converting value using function, expectedErr is the expected error message if any.
+func checkResult(t *testing.T, function string, value interface{}, result interface{}, expected interface{}, err error, expectedErr string) {
+	t.Helper()
+	if expectedErr != "" {
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("%s(%#v) expected error %q, got: %v", function, value, expectedErr, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("%s(%#v) unexpected error: %v", function, value, err)
+		return
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("%s(%#v) = %#v, expected: %#v", function, value, result, expected)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	doc := map[string]interface{}{"port": 80, "MaxConns": 10}
+
+	if value, ok := Lookup(doc, "port"); !ok || value != 80 {
+		t.Errorf("expected port to be 80, got: %v", value)
+	}
+	if value, ok := Lookup(doc, "maxconns"); !ok || value != 10 {
+		t.Errorf("expected maxconns to be matched case insensitively, got: %v", value)
+	}
+	if _, ok := Lookup(doc, "host"); ok {
+		t.Error("expected host not to be found")
+	}
+	if key := Index(Join(Join("", "database"), "hosts"), 2); key != "database.hosts.2" {
+		t.Errorf("expected key database.hosts.2, got: %s", key)
+	}
+}
+
+func TestFieldEnv(t *testing.T) {
+	_ = os.Setenv("LOADER_TEST_PORT", "80")
+	_ = os.Setenv("LOADER_TEST_EMPTY", "")
+	_ = os.Setenv("LOADER_TEST_NAMED", "")
+	defer os.Unsetenv("LOADER_TEST_PORT")
+	defer os.Unsetenv("LOADER_TEST_EMPTY")
+	defer os.Unsetenv("LOADER_TEST_NAMED")
+
+	if value, ok := FieldEnv(EnvName("LOADER_TEST", "PORT")); !ok || value != "80" {
+		t.Errorf("expected the prefixed Environment Variable to be 80, got: %q", value)
+	}
+	if _, ok := FieldEnv("LOADER_TEST_EMPTY"); ok {
+		t.Error("expected an empty prefixed Environment Variable to be ignored")
+	}
+	if value, ok := FieldEnv("LOADER_TEST_PORT", "LOADER_TEST_MISSING", "LOADER_TEST_NAMED"); !ok || value != "" {
+		t.Errorf("expected the set Environment Variable of the names to take precedence, got: %q", value)
+	}
+	if !EnvPrefixed("LOADER_TEST_P") || EnvPrefixed("LOADER_TEST_E") || EnvPrefixed("LOADER_TEST_MISSING") {
+		t.Error("expected only set and not empty Environment Variables to be prefixed")
+	}
+}
+
+func TestValidationFunctions(t *testing.T) {
+	tests := []struct {
+		function func(string) bool
+		name     string
+		valid    []string
+		invalid  []string
+	}{
+		{function: IsURL, name: "IsURL", valid: []string{"https://example.com/a?b=c", "redis://localhost:6379"}, invalid: []string{"example.com", "", "http://a b"}},
+		{function: IsEmail, name: "IsEmail", valid: []string{"admin@example.com"}, invalid: []string{"admin", "Admin <admin@example.com>", ""}},
+		{function: IsIP, name: "IsIP", valid: []string{"10.0.0.1", "::1"}, invalid: []string{"10.0.0", "localhost"}},
+		{function: IsIPv4, name: "IsIPv4", valid: []string{"10.0.0.1"}, invalid: []string{"::1", "::ffff:10.0.0.1", "256.0.0.1"}},
+		{function: IsIPv6, name: "IsIPv6", valid: []string{"::1", "fe80::1"}, invalid: []string{"10.0.0.1", "::g"}},
+		{function: IsHostname, name: "IsHostname", valid: []string{"localhost", "a", "db-1.example.com", "1.example"}, invalid: []string{"-db", "db_1", "a..b", strings.Repeat("a", 64)}},
+		{function: IsHostnameRFC952, name: "IsHostnameRFC952", valid: []string{"localhost", "db-1.example.com"}, invalid: []string{"a", "1.example", "-db", "db_1"}},
+		{function: IsUUID, name: "IsUUID", valid: []string{"123e4567-e89b-12d3-a456-426614174000"}, invalid: []string{"123e4567e89b12d3a456426614174000", ""}},
+	}
+
+	for _, test := range tests {
+		for _, s := range test.valid {
+			if !test.function(s) {
+				t.Errorf("%s(%q) expected to be valid", test.name, s)
+			}
+		}
+		for _, s := range test.invalid {
+			if test.function(s) {
+				t.Errorf("%s(%q) expected to be invalid", test.name, s)
+			}
+		}
+	}
+
+	if Len("héllo") != 5 {
+		t.Errorf("expected Len to count characters, got: %d", Len("héllo"))
+	}
+	if !OneOf("info", "debug", "info") || OneOf("trace", "debug", "info") {
+		t.Error("expected OneOf to match only the listed values")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cause := errors.New("invalid")
+
+	var errs Errors
+	errs.Add("database.port", cause)
+	errs.Addf("", "must be set")
+
+	if errs.Error() != "database.port: invalid; must be set" {
+		t.Fatalf("unexpected errors message: %s", errs.Error())
+	}
+	if !errors.Is(errs[0], cause) {
+		t.Fatal("expected FieldError to unwrap to its cause")
+	}
+}
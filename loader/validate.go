@@ -0,0 +1,102 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sherifabdlnaby/configuro/internal/validators"
+)
+
+//FieldError Error of the value of a config key.
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	if e.Key == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Key, e.Err)
+}
+
+//Unwrap to support errors IS|AS
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+//Errors Error that hold multiple errors.
+type Errors []error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+//Add Add err as the error of key.
+func (e *Errors) Add(key string, err error) {
+	*e = append(*e, &FieldError{Key: key, Err: err})
+}
+
+//Addf Add a formatted error message as the error of key.
+func (e *Errors) Addf(key string, format string, args ...interface{}) {
+	e.Add(key, fmt.Errorf(format, args...))
+}
+
+//Len Returns the number of characters in s.
+func Len(s string) int {
+	return validators.Len(s)
+}
+
+//OneOf Returns whether s is one of values.
+func OneOf(s string, values ...string) bool {
+	for _, value := range values {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
+
+//IsURL Returns whether s is an absolute URL.
+func IsURL(s string) bool {
+	return validators.IsURL(s)
+}
+
+//IsEmail Returns whether s is an email address.
+func IsEmail(s string) bool {
+	return validators.IsEmail(s)
+}
+
+//IsIP Returns whether s is an IPv4 or IPv6 address.
+func IsIP(s string) bool {
+	return validators.IsIP(s)
+}
+
+//IsIPv4 Returns whether s is an IPv4 address.
+func IsIPv4(s string) bool {
+	return validators.IsIPv4(s)
+}
+
+//IsIPv6 Returns whether s is an IPv6 address.
+func IsIPv6(s string) bool {
+	return validators.IsIPv6(s)
+}
+
+//IsHostname Returns whether s is a hostname (RFC 1123).
+func IsHostname(s string) bool {
+	return validators.IsHostname(s)
+}
+
+//IsHostnameRFC952 Returns whether s is a hostname (RFC 952), as the `hostname` rule of go-playground/validator checks.
+func IsHostnameRFC952(s string) bool {
+	return validators.IsHostnameRFC952(s)
+}
+
+//IsUUID Returns whether s is a UUID.
+func IsUUID(s string) bool {
+	return validators.IsUUID(s)
+}
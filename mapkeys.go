@@ -0,0 +1,97 @@
+package configuro
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// recordKeysCase record the original case of every key in a config tree, path is the lower cased key of value.
+// Keys already recorded are overwritten so the case of the last merged file is used.
+func (c *Config) recordKeysCase(value interface{}, path string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			keyPath := c.joinLowerKey(path, key)
+			c.mapKeysCase[keyPath] = key
+			c.recordKeysCase(elem, keyPath)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			c.recordKeysCase(elem, c.joinLowerKey(path, strconv.Itoa(i)))
+		}
+	}
+}
+
+// recordEnvKeyCase record the original case of a key set by an Environment Variable.
+// Environment Variables are bound before reading config files, so the case of keys found in config files take precedence.
+func (c *Config) recordEnvKeyCase(key string, keyDelimiter string) {
+	path := ""
+	for _, k := range strings.Split(key, keyDelimiter) {
+		path = c.joinLowerKey(path, k)
+		if _, recorded := c.mapKeysCase[path]; !recorded {
+			c.mapKeysCase[path] = k
+		}
+	}
+}
+
+func (c *Config) joinLowerKey(path, key string) string {
+	if path == "" {
+		return strings.ToLower(key)
+	}
+	return path + c.keyDelimiter + strings.ToLower(key)
+}
+
+// restoreMapKeysCase return a copy of the config tree where keys of map fields are restored to their original case.
+// t is the type the tree will be decoded into, keys of nested maps in interface{} values are restored too.
+func (c *Config) restoreMapKeysCase(value interface{}, t reflect.Type, path string) interface{} {
+	if t != nil {
+		t = underlyingType(t)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if t != nil && t.Kind() == reflect.Struct {
+			restored := make(map[string]interface{}, len(v))
+			for key, elem := range v {
+				field, found := structFieldByKey(t, key, c.tag)
+				if !found {
+					restored[key] = elem
+					continue
+				}
+				restored[key] = c.restoreMapKeysCase(elem, field.Type, c.joinLowerKey(path, key))
+			}
+			return restored
+		}
+
+		var elemType reflect.Type
+		if t != nil && t.Kind() == reflect.Map {
+			if t.Key().Kind() != reflect.String {
+				return value
+			}
+			elemType = t.Elem()
+		}
+
+		restored := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			keyPath := c.joinLowerKey(path, key)
+			originalKey, recorded := c.mapKeysCase[keyPath]
+			if !recorded {
+				originalKey = key
+			}
+			restored[originalKey] = c.restoreMapKeysCase(elem, elemType, keyPath)
+		}
+		return restored
+	case []interface{}:
+		var elemType reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elemType = t.Elem()
+		}
+		restored := make([]interface{}, len(v))
+		for i, elem := range v {
+			restored[i] = c.restoreMapKeysCase(elem, elemType, c.joinLowerKey(path, strconv.Itoa(i)))
+		}
+		return restored
+	}
+	return value
+}
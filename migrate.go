@@ -0,0 +1,150 @@
+package configuro
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const versionKey = "version"
+
+// migrationsVersion return the current config version, one more than the latest migration.
+func (c *Config) migrationsVersion() int {
+	version := 0
+	for from := range c.migrations {
+		if from+1 > version {
+			version = from + 1
+		}
+	}
+	return version
+}
+
+// migrate upgrade a config document to the current version by applying migrations from its version.
+// It return whether the document was migrated.
+func (c *Config) migrate(doc map[string]interface{}) (bool, error) {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return false, err
+	}
+
+	current := c.migrationsVersion()
+	if version > current {
+		return false, fmt.Errorf("config version %d is newer than the supported version %d", version, current)
+	}
+	if version == current {
+		return false, nil
+	}
+
+	for ; version < current; version++ {
+		migration, ok := c.migrations[version]
+		if !ok {
+			return false, fmt.Errorf("no migration from config version %d", version)
+		}
+		err = migration(doc)
+		if err != nil {
+			return false, fmt.Errorf("error migrating config from version %d: %v", version, err)
+		}
+	}
+	doc[versionKey] = current
+
+	return true, nil
+}
+
+// documentVersion return the version of a config document, documents without a version are at version 0.
+func documentVersion(doc map[string]interface{}) (int, error) {
+	value, ok := doc[versionKey]
+	if !ok {
+		return 0, nil
+	}
+
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		version, err := strconv.Atoi(v)
+		if err == nil {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid config version: %v", value)
+}
+
+// writeMigratedFile write a migrated config document back to its file.
+// Files rendered as templates, files including other files, and JSONC/JSON5 files are not written back,
+// as that would replace the template with its output, inline the included files, or drop the comments.
+func (c *Config) writeMigratedFile(path string, doc map[string]interface{}) error {
+	if c.configTemplate {
+		c.warn("config file \"%s\" was migrated but not written back as it is rendered as a template", path)
+		return nil
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".toml":
+	case ".json":
+		if c.configJSONComments {
+			c.warn("config file \"%s\" was migrated but not written back as its comments would be lost", path)
+			return nil
+		}
+	case ".jsonc", ".json5":
+		c.warn("config file \"%s\" was migrated but not written back as its comments would be lost", path)
+		return nil
+	default:
+		c.warn("config file \"%s\" was migrated but not written back as writing %s files is not supported", path, ext)
+		return nil
+	}
+
+	included, err := c.fileHasIncludes(path)
+	if err != nil {
+		return err
+	}
+	if included {
+		c.warn("config file \"%s\" was migrated but not written back as it includes other config files", path)
+		return nil
+	}
+
+	return c.writeConfigFile(path, doc)
+}
+
+// fileHasIncludes return whether a config file has `$include` keys or YAML `!include` tags.
+func (c *Config) fileHasIncludes(path string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	doc, err := c.decodeConfig(filepath.Ext(path), data)
+	if err != nil {
+		return false, err
+	}
+
+	return hasIncludes(doc), nil
+}
+
+func hasIncludes(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v[includeKey]; ok {
+			return true
+		}
+		for _, elem := range v {
+			if hasIncludes(elem) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, elem := range v {
+			if hasIncludes(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,450 @@
+package configuro
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const secretPlaceholder = "<secret>"
+
+//GenerateSample Generate a commented sample config file for configStruct using the default `config` and `validate` tags.
+// See Config.GenerateSample.
+func GenerateSample(configStruct interface{}, format string) ([]byte, error) {
+	return defaultConfig().GenerateSample(configStruct, format)
+}
+
+//GenerateSample Generate a sample config file for configStruct in format (yaml, json, jsonc, or toml).
+// Every key is included with its value from the `default` tag, or configStruct's value if set, or the zero value.
+// `description` tags and `validate` rules are written as comments (except in json), and fields tagged with
+// `secret:"true"` are shown as a placeholder. Lists and maps of structs are shown with a single example element.
+func (c *Config) GenerateSample(configStruct interface{}, format string) ([]byte, error) {
+	if configStruct == nil {
+		return nil, fmt.Errorf("error generating sample: %T is not a struct", configStruct)
+	}
+	node := c.sampleNode(reflect.TypeOf(configStruct), reflect.ValueOf(configStruct), nil)
+	if node.Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("error generating sample: %T is not a struct", configStruct)
+	}
+
+	switch strings.TrimPrefix(strings.ToLower(format), ".") {
+	case "yaml", "yml":
+		var out bytes.Buffer
+		encoder := yamlv3.NewEncoder(&out)
+		encoder.SetIndent(2)
+		err := encoder.Encode(node)
+		if err != nil {
+			return nil, err
+		}
+		err = encoder.Close()
+		if err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	case "json":
+		return writeSampleJSON(node, false), nil
+	case "jsonc", "json5":
+		return writeSampleJSON(node, true), nil
+	case "toml":
+		return writeSampleTOML(node), nil
+	}
+	return nil, fmt.Errorf("sample format %s is not supported", format)
+}
+
+// sampleNode return the YAML node of a sample value of type t, v is the value set in the config struct (if valid).
+// parents are the struct types being sampled to stop at recursive types.
+func (c *Config) sampleNode(t reflect.Type, v reflect.Value, parents []reflect.Type) *yamlv3.Node {
+	// Values of unexported embedded structs can't be read, they're shown as zero values.
+	if v.IsValid() && !v.CanInterface() {
+		v = reflect.Value{}
+	}
+
+	// Resolve pointers and Value[T] to the underlying value.
+	pointer := false
+	for {
+		if t.Kind() == reflect.Ptr {
+			pointer = true
+			t = t.Elem()
+			if v.IsValid() {
+				v = v.Elem()
+			}
+			continue
+		}
+		if t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(configValueType) {
+			inner := reflect.Value{}
+			if v.IsValid() {
+				value := reflect.New(t)
+				value.Elem().Set(v)
+				if innerValue, isSet := value.Interface().(configValue).get(); isSet {
+					inner = reflect.ValueOf(innerValue)
+				}
+			}
+			t, v = underlyingType(t), inner
+			continue
+		}
+		break
+	}
+	if !v.IsValid() {
+		v = reflect.Zero(t)
+	}
+
+	if t == durationType {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: v.Interface().(time.Duration).String()}
+	}
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, _ := marshaler.MarshalText()
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: string(text)}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, parent := range parents {
+			if parent == t {
+				// A recursive type is shown empty instead of being sampled again inside itself.
+				if pointer {
+					return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+				}
+				return &yamlv3.Node{Kind: yamlv3.MappingNode, Style: yamlv3.FlowStyle}
+			}
+		}
+		node := &yamlv3.Node{Kind: yamlv3.MappingNode}
+		c.sampleStructFields(t, v, node, append(parents[:len(parents):len(parents)], t))
+		return node
+	case reflect.Map:
+		node := &yamlv3.Node{Kind: yamlv3.MappingNode}
+		if v.Len() == 0 {
+			if underlyingType(t.Elem()).Kind() == reflect.Struct {
+				node.Content = append(node.Content,
+					&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "example"},
+					c.sampleNode(t.Elem(), reflect.Value{}, parents))
+			} else {
+				node.Style = yamlv3.FlowStyle
+			}
+			return node
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		for _, key := range keys {
+			node.Content = append(node.Content,
+				&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: fmt.Sprint(key.Interface())},
+				c.sampleNode(t.Elem(), v.MapIndex(key), parents))
+		}
+		return node
+	case reflect.Slice, reflect.Array:
+		node := &yamlv3.Node{Kind: yamlv3.SequenceNode}
+		if v.Len() == 0 {
+			if underlyingType(t.Elem()).Kind() == reflect.Struct {
+				node.Content = append(node.Content, c.sampleNode(t.Elem(), reflect.Value{}, parents))
+			} else {
+				node.Style = yamlv3.FlowStyle
+			}
+			return node
+		}
+		for i := 0; i < v.Len(); i++ {
+			node.Content = append(node.Content, c.sampleNode(t.Elem(), v.Index(i), parents))
+		}
+		return node
+	case reflect.Interface:
+		if v.IsNil() {
+			return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+		}
+		return c.sampleNode(v.Elem().Type(), v.Elem(), parents)
+	}
+
+	var node yamlv3.Node
+	if err := node.Encode(v.Interface()); err != nil {
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	return &node
+}
+
+func (c *Config) sampleStructFields(t reflect.Type, v reflect.Value, node *yamlv3.Node, parents []reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported field
+			continue
+		}
+
+		key, squash := structFieldKey(field, c.tag)
+		if key == "-" {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if squash {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+				fieldValue = fieldValue.Elem()
+			}
+			if !fieldValue.IsValid() {
+				fieldValue = reflect.Zero(fieldType)
+			}
+			if fieldType.Kind() == reflect.Struct {
+				c.sampleStructFields(fieldType, fieldValue, node, parents)
+			}
+			continue
+		}
+
+		var value *yamlv3.Node
+		defaultValue, hasDefault := field.Tag.Lookup(defaultTag)
+		switch {
+		case isSecretField(field):
+			value = &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: secretPlaceholder}
+		case hasDefault:
+			value = sampleDefaultNode(underlyingType(field.Type), defaultValue)
+		default:
+			value = c.sampleNode(field.Type, fieldValue, parents)
+		}
+
+		keyNode := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key, HeadComment: c.sampleComment(field)}
+		node.Content = append(node.Content, keyNode, value)
+	}
+}
+
+// sampleDefaultNode return the YAML node of a `default` tag value.
+// Defaults of lists are JSON arrays or comma separated values, the same way they're decoded.
+func sampleDefaultNode(t reflect.Type, defaultValue string) *yamlv3.Node {
+	switch t.Kind() {
+	case reflect.String:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: defaultValue}
+	case reflect.Slice, reflect.Array:
+		var list []interface{}
+		if json.Unmarshal([]byte(defaultValue), &list) != nil {
+			list = nil
+			for _, elem := range strings.Split(defaultValue, ",") {
+				list = append(list, elem)
+			}
+		}
+		var node yamlv3.Node
+		if err := node.Encode(list); err == nil {
+			node.Style = yamlv3.FlowStyle
+			return &node
+		}
+	}
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: defaultValue}
+}
+
+// sampleComment return the comment of a field in a sample: its description, validation rules, and whether it's a secret.
+func (c *Config) sampleComment(field reflect.StructField) string {
+	var lines []string
+	if description := field.Tag.Get(descriptionTag); description != "" {
+		lines = append(lines, description)
+	}
+	if rules := field.Tag.Get(c.validateTag); rules != "" && rules != "-" {
+		lines = append(lines, "validate: "+rules)
+	}
+	if isSecretField(field) {
+		lines = append(lines, "secret, set it using an Environment Variable.")
+	}
+	for i, line := range lines {
+		lines[i] = "# " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commentLines return the lines of a YAML node comment without the leading '#'.
+func commentLines(comment string) []string {
+	if comment == "" {
+		return nil
+	}
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+	}
+	return lines
+}
+
+// writeSampleJSON write a sample YAML node as JSON, comments are written as `//` comments if enabled (JSONC).
+func writeSampleJSON(node *yamlv3.Node, comments bool) []byte {
+	var out bytes.Buffer
+	writeJSONNode(&out, node, comments, "")
+	out.WriteByte('\n')
+	return out.Bytes()
+}
+
+func writeJSONNode(out *bytes.Buffer, node *yamlv3.Node, comments bool, indent string) {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		if len(node.Content) == 0 {
+			out.WriteString("{}")
+			return
+		}
+		out.WriteString("{\n")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if comments {
+				for _, line := range commentLines(node.Content[i].HeadComment) {
+					out.WriteString(indent + "  // " + line + "\n")
+				}
+			}
+			key, _ := json.Marshal(node.Content[i].Value)
+			out.WriteString(indent + "  " + string(key) + ": ")
+			writeJSONNode(out, node.Content[i+1], comments, indent+"  ")
+			if i+2 < len(node.Content) {
+				out.WriteByte(',')
+			}
+			out.WriteByte('\n')
+		}
+		out.WriteString(indent + "}")
+	case yamlv3.SequenceNode:
+		if len(node.Content) == 0 {
+			out.WriteString("[]")
+			return
+		}
+		out.WriteString("[\n")
+		for i, elem := range node.Content {
+			out.WriteString(indent + "  ")
+			writeJSONNode(out, elem, comments, indent+"  ")
+			if i+1 < len(node.Content) {
+				out.WriteByte(',')
+			}
+			out.WriteByte('\n')
+		}
+		out.WriteString(indent + "]")
+	default:
+		out.WriteString(scalarLiteral(node, true))
+	}
+}
+
+// scalarLiteral return a YAML scalar node as a JSON (or TOML) literal, non JSON/TOML numbers are written as strings.
+// TOML has no null, so nulls are written as empty strings unless allowNull is set.
+func scalarLiteral(node *yamlv3.Node, allowNull bool) string {
+	switch node.ShortTag() {
+	case "!!null":
+		if allowNull {
+			return "null"
+		}
+		return `""`
+	case "!!bool":
+		if value, err := strconv.ParseBool(node.Value); err == nil {
+			return strconv.FormatBool(value)
+		}
+	case "!!int":
+		if value, err := strconv.ParseInt(node.Value, 0, 64); err == nil {
+			return strconv.FormatInt(value, 10)
+		}
+	case "!!float":
+		if value, err := strconv.ParseFloat(node.Value, 64); err == nil {
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return strconv.Quote(node.Value)
+}
+
+// writeSampleTOML write a sample YAML node as TOML, maps are written as tables and lists of maps as arrays of tables.
+func writeSampleTOML(node *yamlv3.Node) []byte {
+	var out bytes.Buffer
+	writeTOMLTable(&out, node, nil)
+	return out.Bytes()
+}
+
+func writeTOMLTable(out *bytes.Buffer, node *yamlv3.Node, path []string) {
+	type table struct {
+		key   *yamlv3.Node
+		value *yamlv3.Node
+	}
+	var tables []table
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if isTOMLTable(value) || isTOMLArrayOfTables(value) {
+			tables = append(tables, table{key: key, value: value})
+			continue
+		}
+		writeTOMLComment(out, key.HeadComment)
+		out.WriteString(tomlKey(key.Value) + " = ")
+		writeTOMLValue(out, value)
+		out.WriteByte('\n')
+	}
+
+	for _, table := range tables {
+		tablePath := append(append([]string(nil), path...), tomlKey(table.key.Value))
+		out.WriteByte('\n')
+		writeTOMLComment(out, table.key.HeadComment)
+		if isTOMLTable(table.value) {
+			out.WriteString("[" + strings.Join(tablePath, ".") + "]\n")
+			writeTOMLTable(out, table.value, tablePath)
+			continue
+		}
+		for i, elem := range table.value.Content {
+			if i > 0 {
+				out.WriteByte('\n')
+			}
+			out.WriteString("[[" + strings.Join(tablePath, ".") + "]]\n")
+			writeTOMLTable(out, elem, tablePath)
+		}
+	}
+}
+
+func isTOMLTable(node *yamlv3.Node) bool {
+	return node.Kind == yamlv3.MappingNode && node.Style != yamlv3.FlowStyle
+}
+
+func isTOMLArrayOfTables(node *yamlv3.Node) bool {
+	if node.Kind != yamlv3.SequenceNode || len(node.Content) == 0 {
+		return false
+	}
+	for _, elem := range node.Content {
+		if elem.Kind != yamlv3.MappingNode {
+			return false
+		}
+	}
+	return true
+}
+
+func writeTOMLValue(out *bytes.Buffer, node *yamlv3.Node) {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		out.WriteString("{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			out.WriteString(" " + tomlKey(node.Content[i].Value) + " = ")
+			writeTOMLValue(out, node.Content[i+1])
+		}
+		if len(node.Content) > 0 {
+			out.WriteString(" ")
+		}
+		out.WriteString("}")
+	case yamlv3.SequenceNode:
+		out.WriteString("[")
+		for i, elem := range node.Content {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			writeTOMLValue(out, elem)
+		}
+		out.WriteString("]")
+	default:
+		out.WriteString(scalarLiteral(node, false))
+	}
+}
+
+func writeTOMLComment(out *bytes.Buffer, comment string) {
+	for _, line := range commentLines(comment) {
+		out.WriteString("# " + line + "\n")
+	}
+}
+
+// tomlKey quote a TOML key unless it's a bare key.
+func tomlKey(key string) string {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return strconv.Quote(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
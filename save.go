@@ -0,0 +1,245 @@
+package configuro
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+//Save Write configStruct to a config file at path, the format is chosen by the file extension (YAML, JSON, or TOML).
+// Keys are named using the config tag, unset Value[T] and nil fields are omitted.
+// The file is written atomically (to a temp file then renamed), and comments of an existing YAML file are kept if enabled.
+func (c *Config) Save(configStruct interface{}, path string) error {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return fmt.Errorf("config file has no extension")
+	}
+	if !isSupportedExtension(ext) {
+		return fmt.Errorf("file with extension %s is not supported", ext)
+	}
+
+	doc, ok := c.encodeValue(reflect.ValueOf(configStruct)).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("error saving config: %T is not a struct or a map", configStruct)
+	}
+
+	err := c.writeConfigFile(path, doc)
+	if err != nil {
+		return fmt.Errorf("error saving config to \"%s\": %v", path, err)
+	}
+	return nil
+}
+
+// writeConfigFile encode a config tree and write it atomically to path.
+func (c *Config) writeConfigFile(path string, doc map[string]interface{}) error {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	var data []byte
+	var err error
+	if c.saveComments && (ext == ".yaml" || ext == ".yml") {
+		data, err = encodeYAMLKeepComments(path, doc)
+	} else {
+		data, err = encodeConfig(ext, doc)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// encodeValue turn a value into a config tree using the config tag for keys, it return nil for values that should be omitted.
+func (c *Config) encodeValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type() == durationType {
+		return v.Interface().(time.Duration).String()
+	}
+	if v.CanInterface() {
+		if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok && v.Kind() != reflect.Ptr {
+			text, err := marshaler.MarshalText()
+			if err == nil {
+				return string(text)
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return c.encodeValue(v.Elem())
+	case reflect.Struct:
+		if reflect.PtrTo(v.Type()).Implements(configValueType) {
+			value := reflect.New(v.Type())
+			value.Elem().Set(v)
+			inner, isSet := value.Interface().(configValue).get()
+			if !isSet {
+				return nil
+			}
+			return c.encodeValue(reflect.ValueOf(inner))
+		}
+		doc := make(map[string]interface{})
+		c.encodeStructFields(v, doc)
+		return doc
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		doc := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			if elem := c.encodeValue(v.MapIndex(key)); elem != nil {
+				doc[fmt.Sprint(key.Interface())] = elem
+			}
+		}
+		return doc
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list[i] = c.encodeValue(v.Index(i))
+		}
+		return list
+	}
+
+	if !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func (c *Config) encodeStructFields(v reflect.Value, doc map[string]interface{}) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported field
+			continue
+		}
+
+		key, squash := structFieldKey(field, c.tag)
+		if key == "-" {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if squash {
+			for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct {
+				c.encodeStructFields(fieldValue, doc)
+			}
+			continue
+		}
+
+		if hasTagOption(field.Tag.Get(c.tag), "omitempty") && fieldValue.IsZero() {
+			continue
+		}
+
+		if value := c.encodeValue(fieldValue); value != nil {
+			doc[key] = value
+		}
+	}
+}
+
+func hasTagOption(tagValue string, option string) bool {
+	for _, opt := range strings.Split(tagValue, ",")[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeYAMLKeepComments encode doc as YAML keeping the comments and keys order of the existing YAML file at path.
+func encodeYAMLKeepComments(path string, doc map[string]interface{}) ([]byte, error) {
+	var value yamlv3.Node
+	err := value.Encode(doc)
+	if err != nil {
+		return nil, err
+	}
+	node := &yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{&value}}
+
+	existing, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		var existingNode yamlv3.Node
+		// An existing file that is not valid YAML is overwritten without its comments.
+		if yamlv3.Unmarshal(existing, &existingNode) == nil {
+			copyYAMLComments(&existingNode, node)
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yamlv3.NewEncoder(&out)
+	encoder.SetIndent(2)
+	err = encoder.Encode(node)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// copyYAMLComments copy comments from the old node to the matching keys (case insensitive) and elements of the new node,
+// keys found in old keep their spelling and order in old and come before new keys.
+func copyYAMLComments(old *yamlv3.Node, new *yamlv3.Node) {
+	new.HeadComment, new.LineComment, new.FootComment = old.HeadComment, old.LineComment, old.FootComment
+
+	switch {
+	case old.Kind == yamlv3.MappingNode && new.Kind == yamlv3.MappingNode:
+		newPairs := make(map[string][]*yamlv3.Node, len(new.Content)/2)
+		var newOrder []string
+		for i := 0; i+1 < len(new.Content); i += 2 {
+			key := strings.ToLower(new.Content[i].Value)
+			newPairs[key] = new.Content[i : i+2]
+			newOrder = append(newOrder, key)
+		}
+
+		content := make([]*yamlv3.Node, 0, len(new.Content))
+		for i := 0; i+1 < len(old.Content); i += 2 {
+			key := strings.ToLower(old.Content[i].Value)
+			pair, found := newPairs[key]
+			if !found {
+				continue
+			}
+			pair[0].Value = old.Content[i].Value
+			pair[0].HeadComment, pair[0].LineComment, pair[0].FootComment = old.Content[i].HeadComment, old.Content[i].LineComment, old.Content[i].FootComment
+			copyYAMLComments(old.Content[i+1], pair[1])
+			content = append(content, pair...)
+			delete(newPairs, key)
+		}
+		for _, key := range newOrder {
+			if pair, found := newPairs[key]; found {
+				content = append(content, pair...)
+			}
+		}
+		new.Content = content
+	case old.Kind == yamlv3.DocumentNode && new.Kind == yamlv3.DocumentNode && len(old.Content) == 1 && len(new.Content) == 1:
+		copyYAMLComments(old.Content[0], new.Content[0])
+	case old.Kind == yamlv3.SequenceNode && new.Kind == yamlv3.SequenceNode:
+		for i := 0; i < len(old.Content) && i < len(new.Content); i++ {
+			copyYAMLComments(old.Content[i], new.Content[i])
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package configuro
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+//JSONSchema Generate a JSON Schema (draft 2020-12) of configStruct using the default `config` and `validate` tags.
+// See Config.JSONSchema.
+func JSONSchema(configStruct interface{}) ([]byte, error) {
+	return defaultConfig().JSONSchema(configStruct)
+}
+
+//JSONSchema Generate a JSON Schema (draft 2020-12) of configStruct, it can be used by editors to complete and validate config files.
+// Properties are named using the config tag, `description` and `default` tags are added as keywords, and the validation rules
+// required, min, max, len, gt, gte, lt, lte, oneof, url, uri, email, hostname, uuid, ip, ipv4, and ipv6 are translated to keywords.
+// Rules after `dive` apply to elements of lists and maps, other rules are ignored.
+func (c *Config) JSONSchema(configStruct interface{}) ([]byte, error) {
+	schema := c.typeSchema(reflect.TypeOf(configStruct), nil)
+	schema["$schema"] = jsonSchemaDraft
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(schema)
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// typeSchema return the schema of values of type t, parents are the struct types being described to stop at recursive types.
+func (c *Config) typeSchema(t reflect.Type, parents []reflect.Type) map[string]interface{} {
+	t = underlyingType(t)
+
+	switch {
+	case t == durationType:
+		return map[string]interface{}{"type": []string{"string", "integer"}}
+	case t == reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.PtrTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": c.typeSchema(t.Elem(), parents)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": c.typeSchema(t.Elem(), parents)}
+	case reflect.Struct:
+		for _, parent := range parents {
+			if parent == t {
+				// Recursive type.
+				return map[string]interface{}{"type": "object"}
+			}
+		}
+		properties := make(map[string]interface{})
+		var required []string
+		c.structSchema(t, append(parents, t), properties, &required)
+
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+
+	return map[string]interface{}{}
+}
+
+func (c *Config) structSchema(t reflect.Type, parents []reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported field
+			continue
+		}
+
+		key, squash := structFieldKey(field, c.tag)
+		if key == "-" {
+			continue
+		}
+		if squash {
+			if fieldType := underlyingType(field.Type); fieldType.Kind() == reflect.Struct {
+				c.structSchema(fieldType, parents, properties, required)
+			}
+			continue
+		}
+
+		schema := c.typeSchema(field.Type, parents)
+		if description := field.Tag.Get(descriptionTag); description != "" {
+			schema["description"] = description
+		}
+		if defaultValue, ok := field.Tag.Lookup(defaultTag); ok {
+			schema["default"] = schemaValue(underlyingType(field.Type), defaultValue)
+		}
+		if isSecretField(field) {
+			schema["writeOnly"] = true
+		}
+
+		if applyValidationRules(schema, underlyingType(field.Type), field.Tag.Get(c.validateTag)) {
+			*required = append(*required, key)
+		}
+		properties[key] = schema
+	}
+}
+
+// applyValidationRules translate validation rules into schema keywords, it return whether the rules make the field required.
+func applyValidationRules(schema map[string]interface{}, t reflect.Type, rules string) bool {
+	required := false
+	for i, rule := range strings.Split(rules, ",") {
+		if rule == "dive" {
+			elemSchema, _ := schema["items"].(map[string]interface{})
+			if t.Kind() == reflect.Map {
+				elemSchema, _ = schema["additionalProperties"].(map[string]interface{})
+			}
+			if elemSchema != nil {
+				applyValidationRules(elemSchema, underlyingType(t.Elem()), strings.Join(strings.Split(rules, ",")[i+1:], ","))
+			}
+			break
+		}
+
+		name, param := rule, ""
+		if j := strings.Index(rule, "="); j >= 0 {
+			name, param = rule[:j], rule[j+1:]
+		}
+
+		switch name {
+		case "required":
+			required = true
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			applyRangeRule(schema, t, name, param)
+		case "oneof":
+			var enum []interface{}
+			for _, value := range strings.Fields(param) {
+				enum = append(enum, schemaValue(t, value))
+			}
+			schema["enum"] = enum
+		case "url", "uri":
+			schema["format"] = "uri"
+		case "email", "hostname", "uuid", "ipv4", "ipv6":
+			schema["format"] = name
+		case "ip":
+			schema["anyOf"] = []interface{}{
+				map[string]interface{}{"format": "ipv4"},
+				map[string]interface{}{"format": "ipv6"},
+			}
+		}
+	}
+	return required
+}
+
+// applyRangeRule translate a range validation rule to the keyword of the field's kind (e.g min on strings is minLength).
+func applyRangeRule(schema map[string]interface{}, t reflect.Type, name string, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	var prefix string
+	switch t.Kind() {
+	case reflect.String:
+		prefix = "Length"
+	case reflect.Slice, reflect.Array:
+		prefix = "Items"
+	case reflect.Map:
+		prefix = "Properties"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch name {
+		case "min", "gte":
+			schema["minimum"] = value
+		case "max", "lte":
+			schema["maximum"] = value
+		case "gt":
+			schema["exclusiveMinimum"] = value
+		case "lt":
+			schema["exclusiveMaximum"] = value
+		case "len":
+			schema["const"] = value
+		}
+		return
+	default:
+		return
+	}
+
+	switch name {
+	case "min", "gte":
+		schema["min"+prefix] = value
+	case "max", "lte":
+		schema["max"+prefix] = value
+	case "gt":
+		schema["min"+prefix] = value + 1
+	case "lt":
+		schema["max"+prefix] = value - 1
+	case "len":
+		schema["min"+prefix] = value
+		schema["max"+prefix] = value
+	}
+}
+
+// schemaValue convert a tag value (e.g a default) to a JSON value of type t, lists are JSON arrays or comma separated values.
+func schemaValue(t reflect.Type, value string) interface{} {
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if t != durationType {
+			if n, err := strconv.ParseInt(value, 0, 64); err == nil {
+				return n
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case reflect.Slice, reflect.Array:
+		var list []interface{}
+		if json.Unmarshal([]byte(value), &list) == nil {
+			return list
+		}
+		for _, elem := range strings.Split(value, ",") {
+			list = append(list, schemaValue(underlyingType(t.Elem()), elem))
+		}
+		return list
+	}
+	return value
+}
@@ -0,0 +1,76 @@
+package configuro
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const configSearchFilename = "config"
+
+//ConfigFilesUsed Return the config files loaded by the last Load() call, ordered from highest to lowest precedence.
+func (c *Config) ConfigFilesUsed() []string {
+	return append([]string(nil), c.configFilesUsed...)
+}
+
+func (c *Config) searchPaths() []string {
+	if len(c.configSearchPaths) > 0 {
+		return c.configSearchPaths
+	}
+
+	paths := []string{"."}
+	if xdgConfigHome, isSet := os.LookupEnv("XDG_CONFIG_HOME"); isSet && xdgConfigHome != "" {
+		paths = append(paths, filepath.Join(xdgConfigHome, "<app>"))
+	}
+	paths = append(paths, filepath.Join("~", ".config", "<app>"), filepath.Join(string(filepath.Separator), "etc", "<app>"))
+	return paths
+}
+
+func (c *Config) expandSearchPath(path string) (string, error) {
+	path = strings.Replace(path, "<app>", c.configSearchAppName, -1)
+	path = os.ExpandEnv(path)
+
+	if path == "~" || strings.HasPrefix(path, "~"+string(filepath.Separator)) || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	return filepath.Abs(path)
+}
+
+// findConfigFiles return the found config files in search paths ordered by precedence (first is the highest).
+func (c *Config) findConfigFiles() ([]string, error) {
+	found := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, searchPath := range c.searchPaths() {
+		dir, err := c.expandSearchPath(searchPath)
+		if err != nil {
+			// Skip paths that can't be resolved (e.g home directory is not set)
+			continue
+		}
+
+		for _, ext := range supportedExt {
+			file := filepath.Join(dir, configSearchFilename+ext)
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+
+			info, err := os.Stat(file)
+			if err != nil || info.IsDir() {
+				continue
+			}
+
+			found = append(found, file)
+			if !c.configSearchMergeAll {
+				return found, nil
+			}
+		}
+	}
+
+	return found, nil
+}
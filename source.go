@@ -0,0 +1,113 @@
+package configuro
+
+import (
+	"strconv"
+	"strings"
+)
+
+//SourceKind The kind of config source a value was loaded from.
+type SourceKind int
+
+const (
+	//SourceNone The value was not set by any config source.
+	SourceNone SourceKind = iota
+	//SourceFile The value was loaded from a config file.
+	SourceFile
+	//SourceEnv The value was loaded from an Environment Variable.
+	SourceEnv
+	//SourceDefault The value was loaded from the field's `default` tag.
+	SourceDefault
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceDefault:
+		return "default"
+	}
+	return "none"
+}
+
+//Source The config source a value was loaded from.
+type Source struct {
+	// Kind of the source.
+	Kind SourceKind
+	// Name of the source, the config file path or the Environment Variable name.
+	Name string
+}
+
+func (s Source) String() string {
+	if s.Name == "" {
+		return s.Kind.String()
+	}
+	return s.Kind.String() + " " + s.Name
+}
+
+//IsSet Returns whether key was set by any config source in the last Load.
+func (c *Config) IsSet(key string) bool {
+	return c.Source(key).Kind != SourceNone
+}
+
+//Source Returns the config source key was loaded from in the last Load.
+// Keys inside a value loaded as a whole (e.g a JSON encoded Environment Variable) have the source of the value,
+// and keys holding nested keys have the source of the nested key with the highest precedence.
+func (c *Config) Source(key string) Source {
+	path := strings.ToLower(key)
+	if source, found := c.sources[path]; found {
+		return source
+	}
+	if source, found := c.parentSources[path]; found {
+		return source
+	}
+	for {
+		i := strings.LastIndex(path, c.keyDelimiter)
+		if i < 0 {
+			return Source{}
+		}
+		path = path[:i]
+		if source, found := c.sources[path]; found {
+			return source
+		}
+	}
+}
+
+// recordSource record source as the source of the value of key, and of the keys holding it.
+// Environment Variables are bound before reading config files, so config files don't override sources of Environment Variables.
+func (c *Config) recordSource(key string, source Source) {
+	path := ""
+	keys := strings.Split(strings.ToLower(key), c.keyDelimiter)
+	for i, k := range keys {
+		path = c.joinLowerKey(path, k)
+		sources := c.parentSources
+		if i == len(keys)-1 {
+			sources = c.sources
+		}
+		if recorded, found := sources[path]; found && recorded.Kind == SourceEnv && source.Kind == SourceFile {
+			continue
+		}
+		sources[path] = source
+	}
+}
+
+// recordTreeSources record source as the source of every value in a config tree, path is the key of value.
+func (c *Config) recordTreeSources(value interface{}, path string, source Source) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && path != "" {
+			c.recordSource(path, source)
+		}
+		for key, elem := range v {
+			c.recordTreeSources(elem, c.joinLowerKey(path, key), source)
+		}
+	case []interface{}:
+		c.recordSource(path, source)
+		for i, elem := range v {
+			c.recordTreeSources(elem, c.joinLowerKey(path, strconv.Itoa(i)), source)
+		}
+	default:
+		c.recordSource(path, source)
+	}
+}
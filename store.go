@@ -0,0 +1,206 @@
+package configuro
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//Store Holds the current loaded and validated config of type T, it's safe for concurrent use.
+// The config returned by Get must not be modified, use Update to change it. Subscribers are notified when the keys they
+// subscribed to change.
+//	store := configuro.NewStore[Config](config)
+//	err := store.Load()
+//	store.Subscribe("database", func(old, new *Config) { reconnect(new.Database) })
+type Store[T any] struct {
+	config  *Config
+	current atomic.Pointer[T]
+
+	// mu serializes loading, updating, and notifying subscribers.
+	mu sync.Mutex
+
+	subscribersMu sync.Mutex
+	subscribers   []*storeSubscriber[T]
+}
+
+type storeSubscriber[T any] struct {
+	key string
+	fn  func(old, new *T)
+}
+
+//NewStore Create a Store of configs of type T that are loaded and validated using config.
+func NewStore[T any](config *Config) *Store[T] {
+	return &Store[T]{config: config}
+}
+
+//Get Returns the current config, or nil if no config was loaded yet.
+func (s *Store[T]) Get() *T {
+	return s.current.Load()
+}
+
+//Load Loads and validates a new config and replaces the current config with it (e.g on startup and on reload).
+// The current config is kept if loading or validation fails.
+func (s *Store[T]) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := new(T)
+	err := s.config.Load(next)
+	if err != nil {
+		return err
+	}
+	return s.set(next)
+}
+
+//Update Applies update to a copy of the current config, validates it, and replaces the current config with it.
+// The current config is kept if validation fails. If no config was loaded yet, update is applied to the zero value of T.
+func (s *Store[T]) Update(update func(config *T)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := new(T)
+	if current := s.current.Load(); current != nil {
+		next = deepCopy(reflect.ValueOf(current)).Interface().(*T)
+	}
+	update(next)
+	return s.set(next)
+}
+
+//Subscribe Calls fn with the old and the new config whenever a value at or nested in key changes (e.g `database` or
+// `database.hosts`), an empty key subscribes to any change. fn is called after the current config is replaced, and old is
+// nil on the first load. fn is called synchronously and must not call Load or Update.
+// Returns a function that unsubscribes fn.
+func (s *Store[T]) Subscribe(key string, fn func(old, new *T)) (unsubscribe func()) {
+	subscriber := &storeSubscriber[T]{key: strings.ToLower(key), fn: fn}
+
+	s.subscribersMu.Lock()
+	s.subscribers = append(s.subscribers, subscriber)
+	s.subscribersMu.Unlock()
+
+	return func() {
+		s.subscribersMu.Lock()
+		defer s.subscribersMu.Unlock()
+		for i, sub := range s.subscribers {
+			if sub == subscriber {
+				// Copy the list so subscribers being notified are not modified.
+				s.subscribers = append(s.subscribers[:i:i], s.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// set validates next, replaces the current config with it, and notifies the subscribers of the changed keys.
+func (s *Store[T]) set(next *T) error {
+	err := s.config.Validate(next)
+	if err != nil {
+		return err
+	}
+
+	old := s.current.Swap(next)
+
+	changes := s.config.Diff(old, next)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	s.subscribersMu.Lock()
+	subscribers := s.subscribers
+	s.subscribersMu.Unlock()
+
+	for _, subscriber := range subscribers {
+		if s.changed(changes, subscriber.key) {
+			subscriber.fn(old, next)
+		}
+	}
+	return nil
+}
+
+// changed return whether any of changes is at, nested in, or a parent of key.
+func (s *Store[T]) changed(changes []Change, key string) bool {
+	if key == "" {
+		return true
+	}
+	for _, change := range changes {
+		if change.Key == key ||
+			strings.HasPrefix(change.Key, key+s.config.keyDelimiter) ||
+			strings.HasPrefix(key, change.Key+s.config.keyDelimiter) {
+			return true
+		}
+	}
+	return false
+}
+
+// deepCopy return a copy of v that doesn't share pointers, maps, and slices with it.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(deepCopy(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(deepCopy(v.Elem()))
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		deepCopyFields(copied)
+		return copied
+	}
+	return v
+}
+
+// deepCopyFields replace the exported fields of the struct v with copies of them, unexported fields are kept as is.
+func deepCopyFields(v reflect.Value) {
+	if v.Addr().CanInterface() {
+		if value, ok := v.Addr().Interface().(configValue); ok {
+			nested := value.reflectValue()
+			nested.Set(deepCopy(nested))
+			return
+		}
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch {
+		case field.CanSet():
+			field.Set(deepCopy(field))
+		case v.Type().Field(i).Anonymous && field.Kind() == reflect.Struct:
+			// exported fields of unexported embedded structs.
+			deepCopyFields(field)
+		}
+	}
+}
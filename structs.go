@@ -0,0 +1,90 @@
+package configuro
+
+import (
+	"reflect"
+	"strings"
+)
+
+const expandTag = "expand"
+
+// structFieldKey return the config key of a struct field according to tagName, and whether it is a squashed embedded struct.
+func structFieldKey(field reflect.StructField, tagName string) (string, bool) {
+	tagValue := field.Tag.Get(tagName)
+	parts := strings.Split(tagValue, ",")
+	squash := false
+	for _, opt := range parts[1:] {
+		if opt == "squash" {
+			squash = true
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], squash
+	}
+	return field.Name, squash
+}
+
+// structFieldByKey find the struct field that a config key will be decoded into (case insensitive), the same way mapstructure does.
+func structFieldByKey(t reflect.Type, key string, tagName string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldKey, squash := structFieldKey(field, tagName)
+
+		if squash {
+			fieldType := underlyingType(field.Type)
+			if fieldType.Kind() == reflect.Struct {
+				if found, ok := structFieldByKey(fieldType, key, tagName); ok {
+					return found, true
+				}
+			}
+			continue
+		}
+
+		if strings.EqualFold(fieldKey, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// walkStructFields call fn for every field of struct type t and its nested structs (through pointers and Value[T]), path is the config key of the field.
+// Squashed embedded structs fields are walked as if they were declared in the parent struct. fn return false to skip walking the field's nested struct.
+// Recursive struct types are walked once per path, a struct type is not walked again inside itself.
+func walkStructFields(t reflect.Type, tagName string, path []string, fn func(path []string, field reflect.StructField) bool) {
+	walkNestedStructFields(t, tagName, path, nil, fn)
+}
+
+// walkNestedStructFields walk the fields of t like walkStructFields, parents are the struct types being walked to stop at recursive types.
+func walkNestedStructFields(t reflect.Type, tagName string, path []string, parents []reflect.Type, fn func(path []string, field reflect.StructField) bool) {
+	t = underlyingType(t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for _, parent := range parents {
+		if parent == t {
+			return
+		}
+	}
+	parents = append(parents[:len(parents):len(parents)], t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported field
+			continue
+		}
+
+		fieldKey, squash := structFieldKey(field, tagName)
+		if fieldKey == "-" {
+			continue
+		}
+		if squash {
+			walkNestedStructFields(field.Type, tagName, path, parents, fn)
+			continue
+		}
+
+		fieldPath := append(append([]string(nil), path...), fieldKey)
+		if fn(fieldPath, field) {
+			walkNestedStructFields(field.Type, tagName, fieldPath, parents, fn)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package configuro
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"text/template"
+)
+
+// renderTemplate render config file content through text/template, template errors contain the file path and line.
+func renderTemplate(path string, data []byte) ([]byte, error) {
+	tmpl, err := template.New(path).Option("missingkey=error").Funcs(templateFuncs(filepath.Dir(path))).Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered bytes.Buffer
+	err = tmpl.Execute(&rendered, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// templateFuncs return the functions available in config templates, relative file paths are resolved against dir.
+func templateFuncs(dir string) template.FuncMap {
+	return template.FuncMap{
+		"env": os.Getenv,
+		"default": func(defaultValue interface{}, value interface{}) interface{} {
+			if isEmptyTemplateValue(value) {
+				return defaultValue
+			}
+			return value
+		},
+		"required": func(message string, value interface{}) (interface{}, error) {
+			if isEmptyTemplateValue(value) {
+				return nil, fmt.Errorf("%s", message)
+			}
+			return value, nil
+		},
+		"file": func(path string) (string, error) {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return string(content), nil
+		},
+		"b64dec": func(encoded string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return "", err
+			}
+			return string(decoded), nil
+		},
+		"toJSON": func(value interface{}) (string, error) {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			return string(encoded), nil
+		},
+		"hostname": os.Hostname,
+	}
+}
+
+func isEmptyTemplateValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
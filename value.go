@@ -0,0 +1,155 @@
+package configuro
+
+import (
+	"reflect"
+	"strconv"
+)
+
+//Value A config value of type T that records whether it was set by any config source, and the source it was loaded from.
+// Use it for fields where an explicitly configured zero value (e.g `debug: false`) must be distinguished from an omitted one.
+//	type Logger struct {
+//		Debug configuro.Value[bool]
+//	}
+type Value[T any] struct {
+	value  T
+	isSet  bool
+	source Source
+}
+
+//Get Returns the loaded value, or the zero value of T if it wasn't set.
+func (v Value[T]) Get() T {
+	return v.value
+}
+
+//Or Returns the loaded value, or defaultValue if it wasn't set.
+func (v Value[T]) Or(defaultValue T) T {
+	if !v.isSet {
+		return defaultValue
+	}
+	return v.value
+}
+
+//IsSet Returns whether the value was set by any config source, including `default` tags.
+func (v Value[T]) IsSet() bool {
+	return v.isSet
+}
+
+//Source Returns the config source the value was loaded from.
+func (v Value[T]) Source() Source {
+	return v.source
+}
+
+func (v *Value[T]) decodeValue(data interface{}, decode func(input interface{}, output interface{}) error) error {
+	err := decode(data, &v.value)
+	if err != nil {
+		return err
+	}
+	v.isSet = true
+	return nil
+}
+
+func (v *Value[T]) get() (interface{}, bool) {
+	return v.value, v.isSet
+}
+
+func (v *Value[T]) setSource(source Source) {
+	if v.isSet {
+		v.source = source
+	}
+}
+
+func (v *Value[T]) valueType() reflect.Type {
+	return reflect.TypeOf(&v.value).Elem()
+}
+
+func (v *Value[T]) reflectValue() reflect.Value {
+	return reflect.ValueOf(&v.value).Elem()
+}
+
+// configValue is implemented by *Value[T].
+type configValue interface {
+	decodeValue(data interface{}, decode func(input interface{}, output interface{}) error) error
+	get() (interface{}, bool)
+	setSource(source Source)
+	valueType() reflect.Type
+	reflectValue() reflect.Value
+}
+
+var configValueType = reflect.TypeOf((*configValue)(nil)).Elem()
+
+// valueDecodeHook decode data into Value[T] target types.
+func (c *Config) valueDecodeHook() func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f == t || !reflect.PtrTo(t).Implements(configValueType) {
+			return data, nil
+		}
+
+		value := reflect.New(t)
+		err := value.Interface().(configValue).decodeValue(data, c.decode)
+		if err != nil {
+			return nil, err
+		}
+		return value.Elem().Interface(), nil
+	}
+}
+
+// underlyingType return the type a config value of type t is decoded into, dereferencing pointers and unwrapping Value[T].
+func underlyingType(t reflect.Type) reflect.Type {
+	for {
+		switch {
+		case t.Kind() == reflect.Ptr:
+			t = t.Elem()
+		case t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(configValueType):
+			t = reflect.New(t).Interface().(configValue).valueType()
+		default:
+			return t
+		}
+	}
+}
+
+// setValueSources set the source of every Value[T] in v after decoding, path is the key v was loaded from.
+func (c *Config) setValueSources(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			c.setValueSources(v.Elem(), path)
+		}
+	case reflect.Struct:
+		if v.CanAddr() && v.Addr().CanInterface() {
+			if value, ok := v.Addr().Interface().(configValue); ok {
+				value.setSource(c.Source(path))
+				return
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			key, squash := structFieldKey(field, c.tag)
+			if key == "-" {
+				continue
+			}
+			if squash {
+				c.setValueSources(v.Field(i), path)
+				continue
+			}
+			c.setValueSources(v.Field(i), c.joinLowerKey(path, key))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.setValueSources(v.Index(i), c.joinLowerKey(path, strconv.Itoa(i)))
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || !v.CanInterface() {
+			return
+		}
+		for _, key := range v.MapKeys() {
+			// Map elements are not addressable, set a copy.
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			c.setValueSources(elem, c.joinLowerKey(path, key.String()))
+			v.SetMapIndex(key, elem)
+		}
+	}
+}